@@ -0,0 +1,108 @@
+package access
+
+import (
+	"strconv"
+	"system-monitor/config"
+)
+
+// Role is the level of access granted to a Telegram user or chat; higher
+// roles include everything allowed to lower ones
+type Role int
+
+// Roles in increasing order of privilege
+const (
+	// RoleNone is not allowed to use the bot
+	RoleNone Role = iota
+	// RoleViewer can read reports, status and charts
+	RoleViewer
+	// RoleOperator can additionally act on services and run commands
+	RoleOperator
+	// RoleAdmin can additionally manage computers and processes
+	RoleAdmin
+)
+
+// ParseRole converts a role name from the config to a Role
+func ParseRole(name string) (Role, bool) {
+	switch name {
+	case config.RoleViewer:
+		return RoleViewer, true
+	case config.RoleOperator:
+		return RoleOperator, true
+	case config.RoleAdmin:
+		return RoleAdmin, true
+	}
+	return RoleNone, false
+}
+
+// String returns the config name of the role
+func (r Role) String() string {
+	switch r {
+	case RoleViewer:
+		return config.RoleViewer
+	case RoleOperator:
+		return config.RoleOperator
+	case RoleAdmin:
+		return config.RoleAdmin
+	}
+	return "none"
+}
+
+// Policy resolves the role of whoever sent an update
+type Policy struct {
+	users map[int64]Role
+	chats map[int64]Role
+}
+
+// New builds a policy from the access section of the config. Without any
+// entries the configured chat_id may view; when it is a private chat, whose
+// ID is the ID of its only user, that user is also admin, as before roles
+// existed. Members of a group chat get no more than viewer from the chat.
+func New(cfg *config.Config) *Policy {
+	p := &Policy{
+		users: make(map[int64]Role),
+		chats: make(map[int64]Role),
+	}
+
+	for id, name := range cfg.Access.Users {
+		p.grant(p.users, id, name)
+	}
+	for id, name := range cfg.Access.Chats {
+		p.grant(p.chats, id, name)
+	}
+
+	if len(p.users) == 0 && len(p.chats) == 0 {
+		p.grant(p.chats, cfg.ChatID, config.RoleViewer)
+		if id, err := strconv.ParseInt(cfg.ChatID, 10, 64); err == nil && id > 0 {
+			p.users[id] = RoleAdmin
+		}
+	}
+
+	return p
+}
+
+// grant adds an entry, skipping IDs and roles that do not parse; config
+// validation reports those
+func (p *Policy) grant(roles map[int64]Role, id, name string) {
+	parsed, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return
+	}
+	if role, ok := ParseRole(name); ok {
+		roles[parsed] = role
+	}
+}
+
+// RoleOf returns the highest role granted to the user or to the chat the
+// update came from. A chat grants at most viewer, so acting on computers
+// always needs a grant for the user.
+func (p *Policy) RoleOf(userID, chatID int64) Role {
+	role := p.users[userID]
+	chatRole := p.chats[chatID]
+	if chatRole > RoleViewer {
+		chatRole = RoleViewer
+	}
+	if chatRole > role {
+		role = chatRole
+	}
+	return role
+}
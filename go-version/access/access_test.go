@@ -0,0 +1,88 @@
+package access
+
+import (
+	"system-monitor/config"
+	"testing"
+)
+
+func TestRoleOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    config.Config
+		userID int64
+		chatID int64
+		want   Role
+	}{
+		{
+			name:   "default group chat member",
+			cfg:    config.Config{ChatID: "-1001234567890"},
+			userID: 42,
+			chatID: -1001234567890,
+			want:   RoleViewer,
+		},
+		{
+			name:   "default private chat owner",
+			cfg:    config.Config{ChatID: "42"},
+			userID: 42,
+			chatID: 42,
+			want:   RoleAdmin,
+		},
+		{
+			name:   "default private chat owner elsewhere",
+			cfg:    config.Config{ChatID: "42"},
+			userID: 42,
+			chatID: -100,
+			want:   RoleAdmin,
+		},
+		{
+			name:   "default stranger",
+			cfg:    config.Config{ChatID: "-1001234567890"},
+			userID: 7,
+			chatID: 7,
+			want:   RoleNone,
+		},
+		{
+			name:   "user grant",
+			cfg:    config.Config{ChatID: "-100", Access: config.Access{Users: map[string]string{"42": "operator"}}},
+			userID: 42,
+			chatID: 42,
+			want:   RoleOperator,
+		},
+		{
+			name:   "chat_id without default once access is set",
+			cfg:    config.Config{ChatID: "-100", Access: config.Access{Users: map[string]string{"42": "admin"}}},
+			userID: 7,
+			chatID: -100,
+			want:   RoleNone,
+		},
+		{
+			name:   "higher of user and chat",
+			cfg:    config.Config{Access: config.Access{Users: map[string]string{"42": "viewer"}, Chats: map[string]string{"-100": "viewer"}}},
+			userID: 42,
+			chatID: -100,
+			want:   RoleViewer,
+		},
+		{
+			name:   "chat grant capped at viewer",
+			cfg:    config.Config{Access: config.Access{Chats: map[string]string{"-100": "admin"}}},
+			userID: 7,
+			chatID: -100,
+			want:   RoleViewer,
+		},
+		{
+			name:   "invalid entries skipped",
+			cfg:    config.Config{Access: config.Access{Users: map[string]string{"abc": "admin", "42": "root"}}},
+			userID: 42,
+			chatID: 42,
+			want:   RoleNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(&tt.cfg).RoleOf(tt.userID, tt.chatID); got != tt.want {
+				t.Errorf("RoleOf(%d, %d) = %v, want %v", tt.userID, tt.chatID, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package access
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Denial is one rejected attempt to use the bot
+type Denial struct {
+	Time     time.Time `json:"time"`
+	UserID   int64     `json:"user_id"`
+	Username string    `json:"username,omitempty"`
+	ChatID   int64     `json:"chat_id"`
+	Command  string    `json:"command"`
+	Role     string    `json:"role"`
+	Required string    `json:"required"`
+}
+
+// Audit appends denied attempts to a JSON lines file
+type Audit struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewAudit creates an audit log writing to path
+func NewAudit(path string) *Audit {
+	return &Audit{path: path}
+}
+
+// Deny records a rejected attempt in the process log and the audit file
+func (a *Audit) Deny(d Denial) {
+	log.Printf("Доступ запрещен: %s (user %d @%s, chat %d, роль %s, требуется %s)",
+		d.Command, d.UserID, d.Username, d.ChatID, d.Role, d.Required)
+
+	if err := a.append(d); err != nil {
+		log.Printf("Ошибка записи журнала доступа: %v", err)
+	}
+}
+
+// append writes one denial as a JSON line
+func (a *Audit) append(d Denial) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	line, err := json.Marshal(d)
+	if err != nil {
+		return fmt.Errorf("failed to marshal denial: %w", err)
+	}
+
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,433 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log"
+	"math"
+	"time"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"system-monitor/monitor"
+	"system-monitor/notify"
+	"system-monitor/storage"
+)
+
+// forecastRefresh is how often disk forecasts are recomputed from history
+const forecastRefresh = 15 * time.Minute
+
+// noGrowth is the forecast value used for disks whose usage is not growing
+const noGrowth = math.MaxFloat64
+
+// Sample holds one set of metric readings used for rule evaluation
+type Sample struct {
+	CPU       *monitor.CPUInfo
+	Memory    *monitor.MemoryInfo
+	Disks     []*monitor.DiskInfo
+	Forecasts []storage.Forecast
+	Services  []monitor.ServiceStatus
+}
+
+// reading is a single value of a metric for one rule target
+type reading struct {
+	key    string
+	target string
+	value  float64
+	// detail describes the value when a number says little, e.g. a service state
+	detail string
+}
+
+// Engine periodically samples metrics and evaluates alert rules
+type Engine struct {
+	cfg          *config.Config
+	store        *storage.Store
+	notifier     notify.Notifier
+	statePath    string
+	states       map[string]*ruleState
+	dirty        bool
+	forecasts    []storage.Forecast
+	forecastedAt time.Time
+	cpu          *monitor.CPUSampler
+}
+
+// stateFile is the name of the persisted alert state file
+const stateFile = "alert_state.json"
+
+// NewEngine creates a new alert engine and restores persisted rule states.
+// The store may be nil, in which case disk forecast rules never fire.
+func NewEngine(cfg *config.Config, store *storage.Store, notifier notify.Notifier) *Engine {
+	statePath := cfg.DataPath(stateFile)
+
+	states, err := loadStates(statePath)
+	if err != nil {
+		log.Printf("Ошибка загрузки состояния тревог: %v", err)
+	}
+
+	// States of removed rules, services and disks would otherwise stay in
+	// the state file forever
+	var mountpoints []string
+	if disks, err := monitor.GetDiskInfo(); err == nil {
+		mountpoints = make([]string, 0, len(disks))
+		for _, disk := range disks {
+			mountpoints = append(mountpoints, disk.Mountpoint)
+		}
+	}
+	dropped := pruneStates(states, cfg.AlertRules(), cfg.Services, mountpoints)
+	if dropped > 0 {
+		log.Printf("Удалено устаревших состояний тревог: %d", dropped)
+	}
+
+	return &Engine{
+		cfg:       cfg,
+		store:     store,
+		notifier:  notifier,
+		statePath: statePath,
+		states:    states,
+		dirty:     dropped > 0,
+		cpu:       monitor.NewCPUSampler(),
+	}
+}
+
+// Run evaluates the rules every interval until ctx is done
+func (e *Engine) Run(ctx context.Context) {
+	interval := e.cfg.Interval()
+	log.Printf("Мониторинг тревог запущен: %d правил, интервал %v", len(e.cfg.AlertRules()), interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	e.Check()
+	for {
+		select {
+		case <-ticker.C:
+			e.Check()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Check collects a sample and evaluates all rules against it
+func (e *Engine) Check() {
+	sample := collect(e.cpu)
+	now := time.Now()
+	sample.Forecasts = e.diskForecasts(now)
+	if e.uses(config.MetricService) {
+		sample.Services = monitor.GetServices(e.cfg.Services)
+	}
+
+	for _, rule := range e.cfg.AlertRules() {
+		for _, r := range readings(rule, sample) {
+			e.evaluate(rule, r, now)
+		}
+	}
+
+	if e.dirty {
+		if err := saveStates(e.statePath, e.states); err != nil {
+			log.Printf("Ошибка сохранения состояния тревог: %v", err)
+		}
+		e.dirty = false
+	}
+}
+
+// collect gathers the metrics used by alert rules, measuring CPU load since
+// the previous collection
+func collect(cpu *monitor.CPUSampler) *Sample {
+	sample := &Sample{}
+
+	if cpuInfo, err := cpu.Info(); err == nil {
+		sample.CPU = cpuInfo
+	} else {
+		log.Printf("Ошибка получения данных CPU: %v", err)
+	}
+
+	if memInfo, err := monitor.GetMemoryInfo(); err == nil {
+		sample.Memory = memInfo
+	} else {
+		log.Printf("Ошибка получения данных памяти: %v", err)
+	}
+
+	if disks, err := monitor.GetDiskInfo(); err == nil {
+		sample.Disks = disks
+	} else {
+		log.Printf("Ошибка получения данных дисков: %v", err)
+	}
+
+	return sample
+}
+
+// diskForecasts returns cached disk forecasts, refreshing them when stale
+func (e *Engine) diskForecasts(now time.Time) []storage.Forecast {
+	if e.store == nil || !e.uses(config.MetricDiskForecast) {
+		return nil
+	}
+
+	if now.Sub(e.forecastedAt) < forecastRefresh {
+		return e.forecasts
+	}
+
+	forecasts, err := e.store.ForecastDisks(now)
+	if err != nil {
+		log.Printf("Ошибка расчета прогноза дисков: %v", err)
+		return e.forecasts
+	}
+
+	e.forecasts = forecasts
+	e.forecastedAt = now
+	return forecasts
+}
+
+// uses reports whether any rule uses a metric
+func (e *Engine) uses(metric string) bool {
+	for _, rule := range e.cfg.AlertRules() {
+		if rule.Metric == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// readings extracts the values a rule applies to from a sample
+func readings(rule config.AlertRule, sample *Sample) []reading {
+	switch rule.Metric {
+	case config.MetricCPU:
+		if sample.CPU != nil {
+			return []reading{{key: rule.Name, value: sample.CPU.Percent}}
+		}
+	case config.MetricMemory:
+		if sample.Memory != nil {
+			return []reading{{key: rule.Name, value: sample.Memory.Percent}}
+		}
+	case config.MetricDisk:
+		var result []reading
+		for _, disk := range sample.Disks {
+			if rule.Mountpoint != "" && rule.Mountpoint != disk.Mountpoint {
+				continue
+			}
+			result = append(result, reading{
+				key:    rule.Name + ":" + disk.Mountpoint,
+				target: disk.Mountpoint,
+				value:  disk.Percent,
+			})
+		}
+		return result
+	case config.MetricDiskForecast:
+		var result []reading
+		for _, forecast := range sample.Forecasts {
+			if rule.Mountpoint != "" && rule.Mountpoint != forecast.Mountpoint {
+				continue
+			}
+			value := noGrowth
+			if forecast.Growing() {
+				value = forecast.DaysLeft
+			}
+			result = append(result, reading{
+				key:    rule.Name + ":" + forecast.Mountpoint,
+				target: forecast.Mountpoint,
+				value:  value,
+			})
+		}
+		return result
+	case config.MetricService:
+		var result []reading
+		for _, service := range sample.Services {
+			if rule.Service != "" && rule.Service != service.Name {
+				continue
+			}
+			value, detail := 0.0, service.State
+			if service.Running {
+				value = 1
+			}
+			if service.Error != "" {
+				detail = service.Error
+			}
+			result = append(result, reading{
+				key:    rule.Name + ":" + service.Name,
+				target: service.Name,
+				value:  value,
+				detail: detail,
+			})
+		}
+		return result
+	}
+	return nil
+}
+
+// evaluate advances the state machine of a rule target and notifies on transitions
+func (e *Engine) evaluate(rule config.AlertRule, r reading, now time.Time) {
+	state, exists := e.states[r.key]
+	if !exists {
+		state = &ruleState{State: StateInactive, Since: now}
+		e.states[r.key] = state
+	}
+	state.Value = r.value
+
+	switch state.State {
+	case StateFiring:
+		// Hysteresis: stay firing until the value crosses back over the resolve level
+		if compare(r.value, rule.Operator, rule.ResolveLevel()) {
+			e.remind(rule, r, state, now)
+			return
+		}
+
+		e.transition(state, StateResolved, now)
+		state.ResolvedAt = now
+		if state.Notified {
+			e.notify(rule, e.formatResolved(rule, r, now.Sub(state.FiredAt)))
+		}
+		state.Notified = false
+
+	case StatePending:
+		if !compare(r.value, rule.Operator, rule.Threshold) {
+			e.transition(state, StateInactive, now)
+			return
+		}
+		if now.Sub(state.Since) >= rule.For() {
+			e.fire(rule, r, state, now)
+		}
+
+	default:
+		if !compare(r.value, rule.Operator, rule.Threshold) {
+			return
+		}
+		e.transition(state, StatePending, now)
+		if rule.For() <= 0 {
+			e.fire(rule, r, state, now)
+		}
+	}
+}
+
+// fire moves a rule target to the firing state, announcing it unless the
+// previous episode resolved within the cooldown period
+func (e *Engine) fire(rule config.AlertRule, r reading, state *ruleState, now time.Time) {
+	e.transition(state, StateFiring, now)
+	state.FiredAt = now
+	state.Notified = false
+
+	if !state.ResolvedAt.IsZero() && now.Sub(state.ResolvedAt) < rule.CooldownDuration() {
+		log.Printf("Тревога %s подавлена (cooldown)", r.key)
+		return
+	}
+
+	e.announce(rule, state, e.formatFiring(rule, r), now)
+}
+
+// remind re-sends a notification for a rule that keeps firing, or announces
+// a suppressed one once its cooldown has passed
+func (e *Engine) remind(rule config.AlertRule, r reading, state *ruleState, now time.Time) {
+	if !state.Notified {
+		if now.Sub(state.ResolvedAt) >= rule.CooldownDuration() {
+			e.announce(rule, state, e.formatFiring(rule, r), now)
+		}
+		return
+	}
+
+	repeat := rule.RepeatDuration()
+	if repeat > 0 && now.Sub(state.LastNotified) >= repeat {
+		e.announce(rule, state, e.formatRepeat(rule, r, now.Sub(state.FiredAt)), now)
+	}
+}
+
+// announce sends a notification and records it in the rule state
+func (e *Engine) announce(rule config.AlertRule, state *ruleState, message string, now time.Time) {
+	e.notify(rule, message)
+	state.Notified = true
+	state.LastNotified = now
+	e.dirty = true
+}
+
+// transition changes the state of a rule target
+func (e *Engine) transition(state *ruleState, to State, now time.Time) {
+	state.State = to
+	state.Since = now
+	e.dirty = true
+}
+
+// notify sends an alert message with the severity of its rule
+func (e *Engine) notify(rule config.AlertRule, message string) {
+	err := e.notifier.Notify(context.Background(), notify.Message{
+		Kind:     config.KindAlert,
+		Severity: rule.SeverityLevel(),
+		Text:     message,
+	})
+	if err != nil {
+		log.Printf("Ошибка отправки тревоги: %v", err)
+	}
+}
+
+// compare applies a rule operator to a value
+func compare(value float64, operator string, threshold float64) bool {
+	switch operator {
+	case ">":
+		return value > threshold
+	case ">=":
+		return value >= threshold
+	case "<":
+		return value < threshold
+	case "<=":
+		return value <= threshold
+	}
+	return false
+}
+
+// metricLabel returns a human-readable name for a rule target, escaped for HTML
+func metricLabel(loc *i18n.Localizer, rule config.AlertRule, r reading) string {
+	switch rule.Metric {
+	case config.MetricCPU:
+		return loc.T("alert.metric.cpu")
+	case config.MetricMemory:
+		return loc.T("alert.metric.memory")
+	case config.MetricDisk:
+		return loc.T("alert.metric.disk", html.EscapeString(r.target))
+	case config.MetricDiskForecast:
+		return loc.T("alert.metric.forecast", html.EscapeString(r.target))
+	}
+	return rule.Metric
+}
+
+// formatValue formats a metric value with its unit
+func formatValue(loc *i18n.Localizer, rule config.AlertRule, value float64) string {
+	if rule.Metric != config.MetricDiskForecast {
+		return fmt.Sprintf("%.1f%%", value)
+	}
+	if value == noGrowth {
+		return loc.T("alert.value.no_growth")
+	}
+	return loc.T("alert.value.days", value)
+}
+
+func (e *Engine) formatFiring(rule config.AlertRule, r reading) string {
+	loc := e.localizer()
+	name, computer := html.EscapeString(rule.Name), html.EscapeString(e.cfg.ComputerName)
+	if rule.Metric == config.MetricService {
+		return loc.T("alert.service_down", name, computer, html.EscapeString(r.target), html.EscapeString(r.detail))
+	}
+	return loc.T("alert.firing", name, computer, metricLabel(loc, rule, r),
+		formatValue(loc, rule, r.value), html.EscapeString(rule.Operator), formatValue(loc, rule, rule.Threshold))
+}
+
+func (e *Engine) formatRepeat(rule config.AlertRule, r reading, elapsed time.Duration) string {
+	loc := e.localizer()
+	name, computer := html.EscapeString(rule.Name), html.EscapeString(e.cfg.ComputerName)
+	if rule.Metric == config.MetricService {
+		return loc.T("alert.service_repeat", name, computer, html.EscapeString(r.target), html.EscapeString(r.detail), loc.Duration(elapsed))
+	}
+	return loc.T("alert.repeat", name, computer, metricLabel(loc, rule, r),
+		formatValue(loc, rule, r.value), html.EscapeString(rule.Operator), formatValue(loc, rule, rule.Threshold), loc.Duration(elapsed))
+}
+
+func (e *Engine) formatResolved(rule config.AlertRule, r reading, elapsed time.Duration) string {
+	loc := e.localizer()
+	name, computer := html.EscapeString(rule.Name), html.EscapeString(e.cfg.ComputerName)
+	if rule.Metric == config.MetricService {
+		return loc.T("alert.service_up", name, computer, html.EscapeString(r.target), loc.Duration(elapsed))
+	}
+	return loc.T("alert.resolved", name, computer, metricLabel(loc, rule, r),
+		formatValue(loc, rule, r.value), loc.Duration(elapsed))
+}
+
+// localizer returns the localizer for the alert chat
+func (e *Engine) localizer() *i18n.Localizer {
+	return i18n.ForChat(e.cfg.ChatID, e.cfg.Language)
+}
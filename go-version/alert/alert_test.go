@@ -0,0 +1,340 @@
+package alert
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"strings"
+	"system-monitor/config"
+	"system-monitor/monitor"
+	"system-monitor/notify"
+	"system-monitor/storage"
+	"testing"
+	"time"
+)
+
+// recorder is a notifier that keeps the messages it was given
+type recorder struct {
+	messages []notify.Message
+}
+
+func (r *recorder) Notify(ctx context.Context, msg notify.Message) error {
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+// kind names a notification by the emoji it starts with
+func kind(msg notify.Message) string {
+	switch {
+	case strings.HasPrefix(msg.Text, "🚨"):
+		return "firing"
+	case strings.HasPrefix(msg.Text, "🔁"):
+		return "repeat"
+	case strings.HasPrefix(msg.Text, "✅"):
+		return "resolved"
+	}
+	return msg.Text
+}
+
+func TestEvaluate(t *testing.T) {
+	resolveAt := 70.0
+
+	type step struct {
+		at    time.Duration
+		value float64
+		// want is the kind of notification sent at this step, if any
+		want  string
+		state State
+	}
+
+	tests := []struct {
+		name  string
+		rule  config.AlertRule
+		steps []step
+	}{
+		{
+			name: "fires after duration",
+			rule: config.AlertRule{Metric: config.MetricCPU, Operator: ">", Threshold: 90, Duration: "2m"},
+			steps: []step{
+				{at: 0, value: 95, state: StatePending},
+				{at: time.Minute, value: 95, state: StatePending},
+				{at: 2 * time.Minute, value: 95, want: "firing", state: StateFiring},
+				{at: 3 * time.Minute, value: 95, state: StateFiring},
+				{at: 4 * time.Minute, value: 50, want: "resolved", state: StateResolved},
+			},
+		},
+		{
+			name: "fires at once without duration",
+			rule: config.AlertRule{Metric: config.MetricCPU, Operator: ">", Threshold: 90},
+			steps: []step{
+				{at: 0, value: 80, state: StateInactive},
+				{at: time.Minute, value: 91, want: "firing", state: StateFiring},
+			},
+		},
+		{
+			name: "pending resets when value drops",
+			rule: config.AlertRule{Metric: config.MetricCPU, Operator: ">", Threshold: 90, Duration: "2m"},
+			steps: []step{
+				{at: 0, value: 95, state: StatePending},
+				{at: time.Minute, value: 85, state: StateInactive},
+				{at: 2 * time.Minute, value: 95, state: StatePending},
+				{at: 3 * time.Minute, value: 95, state: StatePending},
+				{at: 4 * time.Minute, value: 95, want: "firing", state: StateFiring},
+			},
+		},
+		{
+			name: "hysteresis keeps firing above resolve threshold",
+			rule: config.AlertRule{Metric: config.MetricMemory, Operator: ">", Threshold: 90, ResolveThreshold: &resolveAt},
+			steps: []step{
+				{at: 0, value: 95, want: "firing", state: StateFiring},
+				{at: time.Minute, value: 85, state: StateFiring},
+				{at: 2 * time.Minute, value: 71, state: StateFiring},
+				{at: 3 * time.Minute, value: 70, want: "resolved", state: StateResolved},
+			},
+		},
+		{
+			name: "repeats while firing",
+			rule: config.AlertRule{Metric: config.MetricCPU, Operator: ">", Threshold: 90, RepeatInterval: "10m"},
+			steps: []step{
+				{at: 0, value: 95, want: "firing", state: StateFiring},
+				{at: 5 * time.Minute, value: 95, state: StateFiring},
+				{at: 10 * time.Minute, value: 95, want: "repeat", state: StateFiring},
+				{at: 15 * time.Minute, value: 95, state: StateFiring},
+				{at: 20 * time.Minute, value: 95, want: "repeat", state: StateFiring},
+			},
+		},
+		{
+			name: "cooldown suppresses refiring",
+			rule: config.AlertRule{Metric: config.MetricCPU, Operator: ">", Threshold: 90, Cooldown: "10m"},
+			steps: []step{
+				{at: 0, value: 95, want: "firing", state: StateFiring},
+				{at: time.Minute, value: 50, want: "resolved", state: StateResolved},
+				{at: 2 * time.Minute, value: 95, state: StateFiring},
+				{at: 5 * time.Minute, value: 95, state: StateFiring},
+				// Announced once the cooldown since the resolve has passed
+				{at: 11 * time.Minute, value: 95, want: "firing", state: StateFiring},
+			},
+		},
+		{
+			name: "suppressed alert resolves silently",
+			rule: config.AlertRule{Metric: config.MetricCPU, Operator: ">", Threshold: 90, Cooldown: "10m"},
+			steps: []step{
+				{at: 0, value: 95, want: "firing", state: StateFiring},
+				{at: time.Minute, value: 50, want: "resolved", state: StateResolved},
+				{at: 2 * time.Minute, value: 95, state: StateFiring},
+				{at: 3 * time.Minute, value: 50, state: StateResolved},
+			},
+		},
+		{
+			name: "refires after cooldown",
+			rule: config.AlertRule{Metric: config.MetricCPU, Operator: ">", Threshold: 90, Cooldown: "10m"},
+			steps: []step{
+				{at: 0, value: 95, want: "firing", state: StateFiring},
+				{at: time.Minute, value: 50, want: "resolved", state: StateResolved},
+				{at: 20 * time.Minute, value: 95, want: "firing", state: StateFiring},
+			},
+		},
+		{
+			name: "less than operator",
+			rule: config.AlertRule{Metric: config.MetricDiskForecast, Operator: "<", Threshold: 7},
+			steps: []step{
+				{at: 0, value: noGrowth, state: StateInactive},
+				{at: time.Hour, value: 5, want: "firing", state: StateFiring},
+				{at: 2 * time.Hour, value: 8, want: "resolved", state: StateResolved},
+			},
+		},
+	}
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.rule.Name = "test"
+			notifier := &recorder{}
+			e := &Engine{
+				cfg:      &config.Config{ComputerName: "host", Language: "en"},
+				notifier: notifier,
+				states:   make(map[string]*ruleState),
+			}
+
+			for i, s := range tt.steps {
+				sent := len(notifier.messages)
+				e.evaluate(tt.rule, reading{key: "test", target: "/", value: s.value}, start.Add(s.at))
+
+				got := ""
+				switch len(notifier.messages) - sent {
+				case 0:
+				case 1:
+					got = kind(notifier.messages[sent])
+				default:
+					t.Fatalf("step %d: sent %d messages", i, len(notifier.messages)-sent)
+				}
+				if got != s.want {
+					t.Errorf("step %d: notification %q, want %q", i, got, s.want)
+				}
+				if state := e.states["test"].State; state != s.state {
+					t.Errorf("step %d: state %v, want %v", i, state, s.state)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateSeverity(t *testing.T) {
+	notifier := &recorder{}
+	e := &Engine{
+		cfg:      &config.Config{ComputerName: "host", Language: "en"},
+		notifier: notifier,
+		states:   make(map[string]*ruleState),
+	}
+
+	rule := config.AlertRule{Name: "test", Metric: config.MetricCPU, Operator: ">", Threshold: 90, Severity: config.SeverityCritical}
+	e.evaluate(rule, reading{key: "test", value: 95}, time.Now())
+
+	if len(notifier.messages) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(notifier.messages))
+	}
+	msg := notifier.messages[0]
+	if msg.Kind != config.KindAlert || msg.Severity != config.SeverityCritical {
+		t.Errorf("message kind %q severity %q, want %q %q", msg.Kind, msg.Severity, config.KindAlert, config.SeverityCritical)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		value     float64
+		operator  string
+		threshold float64
+		want      bool
+	}{
+		{91, ">", 90, true},
+		{90, ">", 90, false},
+		{90, ">=", 90, true},
+		{89, "<", 90, true},
+		{90, "<", 90, false},
+		{90, "<=", 90, true},
+		{95, "==", 90, false},
+	}
+
+	for _, tt := range tests {
+		if got := compare(tt.value, tt.operator, tt.threshold); got != tt.want {
+			t.Errorf("compare(%v, %q, %v) = %v, want %v", tt.value, tt.operator, tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestReadings(t *testing.T) {
+	sample := &Sample{
+		CPU:    &monitor.CPUInfo{Percent: 42},
+		Memory: &monitor.MemoryInfo{Percent: 61},
+		Disks: []*monitor.DiskInfo{
+			{Mountpoint: "/", Percent: 70},
+			{Mountpoint: "/data", Percent: 95},
+		},
+		Forecasts: []storage.Forecast{
+			{Mountpoint: "/", DaysLeft: math.Inf(1)},
+			{Mountpoint: "/data", DaysLeft: 3},
+		},
+		Services: []monitor.ServiceStatus{
+			{Name: "nginx", State: "active", Running: true},
+			{Name: "postgres", State: "failed"},
+			{Name: "redis", Error: "not found"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		rule config.AlertRule
+		want []reading
+	}{
+		{
+			name: "cpu",
+			rule: config.AlertRule{Name: "cpu", Metric: config.MetricCPU},
+			want: []reading{{key: "cpu", value: 42}},
+		},
+		{
+			name: "memory",
+			rule: config.AlertRule{Name: "mem", Metric: config.MetricMemory},
+			want: []reading{{key: "mem", value: 61}},
+		},
+		{
+			name: "every disk",
+			rule: config.AlertRule{Name: "disk", Metric: config.MetricDisk},
+			want: []reading{
+				{key: "disk:/", target: "/", value: 70},
+				{key: "disk:/data", target: "/data", value: 95},
+			},
+		},
+		{
+			name: "one disk",
+			rule: config.AlertRule{Name: "disk", Metric: config.MetricDisk, Mountpoint: "/data"},
+			want: []reading{{key: "disk:/data", target: "/data", value: 95}},
+		},
+		{
+			name: "forecast",
+			rule: config.AlertRule{Name: "full", Metric: config.MetricDiskForecast},
+			want: []reading{
+				{key: "full:/", target: "/", value: noGrowth},
+				{key: "full:/data", target: "/data", value: 3},
+			},
+		},
+		{
+			name: "services",
+			rule: config.AlertRule{Name: "down", Metric: config.MetricService},
+			want: []reading{
+				{key: "down:nginx", target: "nginx", value: 1, detail: "active"},
+				{key: "down:postgres", target: "postgres", value: 0, detail: "failed"},
+				{key: "down:redis", target: "redis", value: 0, detail: "not found"},
+			},
+		},
+		{
+			name: "unknown metric",
+			rule: config.AlertRule{Name: "load", Metric: "load"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := readings(tt.rule, sample); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readings() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadingsWithoutData(t *testing.T) {
+	for _, metric := range []string{config.MetricCPU, config.MetricMemory, config.MetricDisk} {
+		if got := readings(config.AlertRule{Name: "x", Metric: metric}, &Sample{}); len(got) != 0 {
+			t.Errorf("%s: got readings %+v from an empty sample", metric, got)
+		}
+	}
+}
+
+func TestFormatEscapesNames(t *testing.T) {
+	e := &Engine{cfg: &config.Config{ComputerName: "<db & co>", Language: "en"}}
+
+	tests := []struct {
+		name string
+		rule config.AlertRule
+		r    reading
+	}{
+		{"disk", config.AlertRule{Name: "<disk>", Metric: config.MetricDisk, Operator: ">"}, reading{target: "/mnt/<x>", value: 95}},
+		{"service", config.AlertRule{Name: "<svc>", Metric: config.MetricService, Operator: "<"}, reading{target: "a&b", detail: "<failed>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := []string{
+				e.formatFiring(tt.rule, tt.r),
+				e.formatRepeat(tt.rule, tt.r, time.Hour),
+				e.formatResolved(tt.rule, tt.r, time.Hour),
+			}
+			for _, msg := range messages {
+				// Only the tags of the message template may remain
+				stripped := strings.NewReplacer("<b>", "", "</b>", "").Replace(msg)
+				if strings.ContainsAny(stripped, "<>") || strings.Contains(stripped, "& ") {
+					t.Errorf("unescaped text in %q", msg)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package alert
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"system-monitor/config"
+	"time"
+)
+
+// State is the lifecycle state of an alert rule target
+type State string
+
+const (
+	StateInactive State = "inactive"
+	StatePending  State = "pending"
+	StateFiring   State = "firing"
+	StateResolved State = "resolved"
+)
+
+// ruleState tracks the evaluation state of one rule target
+type ruleState struct {
+	State        State     `json:"state"`
+	Since        time.Time `json:"since"`
+	FiredAt      time.Time `json:"fired_at,omitempty"`
+	ResolvedAt   time.Time `json:"resolved_at,omitempty"`
+	LastNotified time.Time `json:"last_notified,omitempty"`
+	Notified     bool      `json:"notified"`
+	Value        float64   `json:"value"`
+}
+
+// loadStates reads persisted rule states from disk
+func loadStates(path string) (map[string]*ruleState, error) {
+	states := make(map[string]*ruleState)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return states, nil
+	}
+	if err != nil {
+		return states, fmt.Errorf("failed to read alert state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &states); err != nil {
+		return make(map[string]*ruleState), fmt.Errorf("failed to parse alert state: %w", err)
+	}
+
+	return states, nil
+}
+
+// pruneStates drops the states of rule targets that are no longer
+// configured, e.g. after a rule was renamed or removed, and returns how many
+// were dropped. mountpoints is nil when the disks could not be listed; the
+// states of disk rules are then kept.
+func pruneStates(states map[string]*ruleState, rules []config.AlertRule, services, mountpoints []string) int {
+	keep := make(map[string]bool)
+	var keepPrefixes []string
+	for _, rule := range rules {
+		switch rule.Metric {
+		case config.MetricDisk, config.MetricDiskForecast:
+			if mountpoints == nil {
+				keepPrefixes = append(keepPrefixes, rule.Name+":")
+			}
+			for _, mountpoint := range mountpoints {
+				if rule.Mountpoint == "" || rule.Mountpoint == mountpoint {
+					keep[rule.Name+":"+mountpoint] = true
+				}
+			}
+		case config.MetricService:
+			for _, service := range services {
+				if rule.Service == "" || rule.Service == service {
+					keep[rule.Name+":"+service] = true
+				}
+			}
+		default:
+			keep[rule.Name] = true
+		}
+	}
+
+	dropped := 0
+	for key := range states {
+		if keep[key] || hasAnyPrefix(key, keepPrefixes) {
+			continue
+		}
+		delete(states, key)
+		dropped++
+	}
+	return dropped
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// saveStates writes rule states to disk atomically
+func saveStates(path string, states map[string]*ruleState) error {
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write alert state: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace alert state: %w", err)
+	}
+
+	return nil
+}
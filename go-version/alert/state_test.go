@@ -0,0 +1,83 @@
+package alert
+
+import (
+	"path/filepath"
+	"reflect"
+	"sort"
+	"system-monitor/config"
+	"testing"
+	"time"
+)
+
+func TestPruneStates(t *testing.T) {
+	rules := []config.AlertRule{
+		{Name: "cpu", Metric: config.MetricCPU},
+		{Name: "disk", Metric: config.MetricDisk},
+		{Name: "data", Metric: config.MetricDiskForecast, Mountpoint: "/data"},
+		{Name: "down", Metric: config.MetricService},
+	}
+	keys := []string{
+		"cpu", "memory",
+		"disk:/", "disk:/data", "disk:/mnt/usb",
+		"data:/", "data:/data",
+		"down:nginx", "down:redis",
+	}
+
+	tests := []struct {
+		name        string
+		mountpoints []string
+		want        []string
+	}{
+		{
+			name:        "removed rules and targets",
+			mountpoints: []string{"/", "/data"},
+			want:        []string{"cpu", "data:/data", "disk:/", "disk:/data", "down:nginx"},
+		},
+		{
+			name: "disks unknown",
+			want: []string{"cpu", "data:/", "data:/data", "disk:/", "disk:/data", "disk:/mnt/usb", "down:nginx"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			states := make(map[string]*ruleState)
+			for _, key := range keys {
+				states[key] = &ruleState{State: StateFiring}
+			}
+
+			dropped := pruneStates(states, rules, []string{"nginx"}, tt.mountpoints)
+
+			var got []string
+			for key := range states {
+				got = append(got, key)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("kept %q, want %q", got, tt.want)
+			}
+			if dropped != len(keys)-len(tt.want) {
+				t.Errorf("dropped %d, want %d", dropped, len(keys)-len(tt.want))
+			}
+		})
+	}
+}
+
+func TestStatesRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), stateFile)
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	states := map[string]*ruleState{
+		"cpu": {State: StateFiring, Since: since, FiredAt: since, Notified: true, Value: 95},
+	}
+
+	if err := saveStates(path, states); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadStates(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, states) {
+		t.Errorf("loaded %+v, want %+v", loaded["cpu"], states["cpu"])
+	}
+}
@@ -1,22 +1,50 @@
 package bot
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
+	"html"
 	"log"
-	"net/http"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"system-monitor/access"
+	"system-monitor/chart"
 	"system-monitor/config"
+	"system-monitor/hub"
+	"system-monitor/i18n"
+	"system-monitor/registry"
+	"system-monitor/report"
+	"system-monitor/storage"
 	"system-monitor/telegram"
 )
 
 const (
-	telegramAPIURL = "https://api.telegram.org/bot%s"
 	pollTimeout    = 30
+	defaultPeriod  = "24h"
 )
 
+// commandRoles is the minimum role each command requires; commands not
+// listed here require the viewer role
+var commandRoles = map[string]access.Role{
+	"/info":      access.RoleViewer,
+	"/status":    access.RoleViewer,
+	"/computers": access.RoleViewer,
+	"/chart":     access.RoleViewer,
+	"/lang":      access.RoleViewer,
+	"/help":      access.RoleViewer,
+	"/start":     access.RoleViewer,
+	"/top":       access.RoleViewer,
+	"/proc":      access.RoleAdmin,
+	"/rename":    access.RoleAdmin,
+	"/forget":    access.RoleAdmin,
+	"/kill":      access.RoleAdmin,
+	"/services":  access.RoleViewer,
+	"/restart":   access.RoleOperator,
+	"/run":       access.RoleViewer,
+}
+
 // Update represents a Telegram update
 type Update struct {
 	UpdateID int `json:"update_id"`
@@ -64,109 +92,126 @@ type InlineKeyboardButton struct {
 	CallbackData string `json:"callback_data,omitempty"`
 }
 
-// ComputerInfo stores active computer information
-type ComputerInfo struct {
-	ID       string
-	Name     string
-	LastSeen time.Time
-}
-
 // Poller manages Telegram bot polling
 type Poller struct {
-	token         string
-	chatID        string
 	cfg           *config.Config
+	client        *telegram.Client
+	store         *storage.Store
 	offset        int
-	computers     map[string]*ComputerInfo
-	computersMux  sync.RWMutex
+	computers     *registry.Registry
+	hub           *hub.Server
+	policy        *access.Policy
+	audit         *access.Audit
+	// commands tracks /run commands still running in the background
+	commands      sync.WaitGroup
 }
 
-// NewPoller creates a new poller
-func NewPoller(cfg *config.Config) *Poller {
+// NewPoller creates a new poller. The store may be nil when history is
+// unavailable; hubServer is nil unless this process runs as a hub.
+func NewPoller(cfg *config.Config, store *storage.Store, computers *registry.Registry, hubServer *hub.Server, client *telegram.Client) *Poller {
 	return &Poller{
-		token:     cfg.TelegramToken,
-		chatID:    cfg.ChatID,
 		cfg:       cfg,
-		computers: make(map[string]*ComputerInfo),
+		client:    client,
+		store:     store,
+		computers: computers,
+		hub:       hubServer,
+		policy:    access.New(cfg),
+		audit:     access.NewAudit(cfg.Access.AuditLog),
 	}
 }
 
 // RegisterComputer registers this computer
 func (p *Poller) RegisterComputer() {
-	p.computersMux.Lock()
-	defer p.computersMux.Unlock()
-	
-	p.computers[p.cfg.ComputerID] = &ComputerInfo{
-		ID:       p.cfg.ComputerID,
-		Name:     p.cfg.ComputerName,
-		LastSeen: time.Now(),
-	}
+	p.computers.Touch(p.localInfo())
 }
 
 // UpdateLastSeen updates computer's last seen time
 func (p *Poller) UpdateLastSeen() {
-	p.computersMux.Lock()
-	defer p.computersMux.Unlock()
-	
-	if comp, exists := p.computers[p.cfg.ComputerID]; exists {
-		comp.LastSeen = time.Now()
+	p.computers.Touch(p.localInfo())
+}
+
+// localInfo describes this computer for the registry
+func (p *Poller) localInfo() registry.Info {
+	return registry.Info{
+		ID:    p.cfg.ComputerID,
+		Name:  p.cfg.ComputerName,
+		Tags:  p.cfg.Tags,
+		Group: p.cfg.Group,
+		Owner: p.cfg.Owner,
+		Local: true,
 	}
 }
 
-// StartPolling starts the polling loop
-func (p *Poller) StartPolling() {
+// StartPolling answers updates until ctx is done
+func (p *Poller) StartPolling(ctx context.Context) {
 	log.Println("Запуск Telegram polling...")
 	
 	// Register this computer
 	p.RegisterComputer()
 	
-	for {
-		updates, err := p.getUpdates()
+	// getUpdates is rejected while a webhook left by webhook mode is set
+	if err := p.deleteWebhook(); err != nil {
+		log.Printf("Ошибка удаления webhook: %v", err)
+	}
+	
+	for ctx.Err() == nil {
+		updates, err := p.getUpdates(ctx, pollTimeout)
+		if ctx.Err() != nil {
+			break
+		}
 		if err != nil {
 			log.Printf("Ошибка получения обновлений: %v", err)
-			time.Sleep(5 * time.Second)
+			select {
+			case <-time.After(5 * time.Second):
+			case <-ctx.Done():
+			}
 			continue
 		}
 
 		for _, update := range updates {
-			p.processUpdate(update)
+			p.processUpdate(ctx, update)
 			p.offset = update.UpdateID + 1
 		}
 	}
-}
 
-// getUpdates fetches updates from Telegram
-func (p *Poller) getUpdates() ([]Update, error) {
-	url := fmt.Sprintf(telegramAPIURL+"/getUpdates?offset=%d&timeout=%d", 
-		p.token, p.offset, pollTimeout)
+	// Commands are cancelled with ctx; wait for them to report back
+	p.commands.Wait()
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, err
+	// Confirm the processed updates, so they are not delivered again
+	// after a restart
+	ackCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := p.getUpdates(ackCtx, 0); err != nil {
+		log.Printf("Ошибка подтверждения обновлений: %v", err)
 	}
-	defer resp.Body.Close()
+}
 
-	var response struct {
-		OK     bool     `json:"ok"`
-		Result []Update `json:"result"`
+// getUpdates fetches updates from Telegram, waiting up to timeout seconds
+// for new ones
+func (p *Poller) getUpdates(ctx context.Context, timeout int) ([]Update, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout+10)*time.Second)
+	defer cancel()
+	
+	payload := map[string]interface{}{
+		"offset":          p.offset,
+		"timeout":         timeout,
+		"allowed_updates": []string{"message", "callback_query"},
 	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+	
+	var updates []Update
+	if err := p.client.Call(ctx, "getUpdates", payload, &updates); err != nil {
 		return nil, err
 	}
-
-	if !response.OK {
-		return nil, fmt.Errorf("telegram API error")
-	}
-
-	return response.Result, nil
+	
+	return updates, nil
 }
 
-// processUpdate processes a single update
-func (p *Poller) processUpdate(update Update) {
+// processUpdate processes a single update; work started in the background
+// ends with ctx
+func (p *Poller) processUpdate(ctx context.Context, update Update) {
 	// Handle text messages (commands)
 	if update.Message != nil && update.Message.Text != "" {
-		p.handleCommand(update.Message)
+		p.handleCommand(ctx, update.Message)
 		return
 	}
 
@@ -178,115 +223,388 @@ func (p *Poller) processUpdate(update Update) {
 }
 
 // handleCommand processes text commands
-func (p *Poller) handleCommand(msg *Message) {
-	command := msg.Text
+func (p *Poller) handleCommand(ctx context.Context, msg *Message) {
+	log.Printf("Получена команда: %s от %s", msg.Text, senderName(msg.From))
 	
-	log.Printf("Получена команда: %s от %s", command, msg.From.Username)
+	fields := strings.Fields(msg.Text)
+	if len(fields) == 0 {
+		return
+	}
+	
+	// Strip the bot mention used in group chats, e.g. /chart@my_bot
+	command, _, _ := strings.Cut(fields[0], "@")
+	args := fields[1:]
+	
+	chatID := strconv.FormatInt(msg.Chat.ID, 10)
+	loc := i18n.ForChat(chatID, p.cfg.Language)
+	
+	required, known := commandRoles[command]
+	if !known {
+		required = access.RoleViewer
+	}
+	if !p.authorize(msg.From, msg.Chat.ID, command, required, loc) {
+		return
+	}
 	
 	switch command {
 	case "/info":
-		p.handleInfo()
+		p.handleInfo(chatID, loc, args)
 	case "/status":
-		p.handleStatus()
+		p.handleStatus(chatID, loc, args)
+	case "/computers":
+		p.handleComputers(chatID, loc, args)
+	case "/rename":
+		p.handleRename(chatID, loc, args)
+	case "/forget":
+		p.handleForget(chatID, loc, args)
+	case "/chart":
+		p.handleChart(chatID, loc, args)
+	case "/top":
+		p.handleTop(chatID, loc, args)
+	case "/proc":
+		p.handleProc(chatID, loc, args)
+	case "/kill":
+		p.handleKill(chatID, loc, args)
+	case "/services":
+		p.handleServices(chatID, loc)
+	case "/restart":
+		p.handleRestart(chatID, loc, args)
+	case "/run":
+		p.handleRun(ctx, msg, loc, args)
+	case "/lang":
+		p.handleLang(chatID, loc, args)
 	case "/help", "/start":
-		p.handleHelp()
+		p.handleHelp(chatID, loc)
 	default:
-		p.sendMessage("Неизвестная команда. Используйте /help для списка команд.")
+		p.sendMessage(chatID, loc.T("bot.unknown_command"))
 	}
 }
 
-// handleInfo shows computer selection menu
-func (p *Poller) handleInfo() {
-	p.computersMux.RLock()
-	defer p.computersMux.RUnlock()
+// authorize checks the sender's role against the one a command requires;
+// denied attempts are audited and answered in the chat they came from
+func (p *Poller) authorize(from *User, chatID int64, command string, required access.Role, loc *i18n.Localizer) bool {
+	userID := senderID(from)
+	role := p.policy.RoleOf(userID, chatID)
+	if role >= required {
+		return true
+	}
+	
+	p.audit.Deny(access.Denial{
+		Time:     time.Now(),
+		UserID:   userID,
+		Username: senderName(from),
+		ChatID:   chatID,
+		Command:  command,
+		Role:     role.String(),
+		Required: required.String(),
+	})
 	
-	if len(p.computers) == 0 {
-		p.sendMessage("Нет доступных компьютеров")
+	reply := strconv.FormatInt(chatID, 10)
+	if role == access.RoleNone {
+		p.sendMessage(reply, loc.T("bot.access_denied", userID))
+	} else {
+		p.sendMessage(reply, loc.T("bot.access_insufficient", command, required.String()))
+	}
+	return false
+}
+
+// handleInfo shows computer selection menu, optionally filtered
+func (p *Poller) handleInfo(chatID string, loc *i18n.Localizer, args []string) {
+	computers := p.computers.Find(registry.ParseFilter(args))
+	if len(computers) == 0 {
+		p.sendMessage(chatID, loc.T("bot.no_computers"))
 		return
 	}
 	
 	// Create keyboard with computer buttons
-	keyboard := p.createComputerKeyboard()
-	p.sendKeyboard("Выберите компьютер:", keyboard)
+	keyboard := p.createComputerKeyboard(computers)
+	p.sendKeyboard(chatID, loc.T("bot.choose_computer"), keyboard)
 }
 
-// handleStatus shows brief status of all computers
-func (p *Poller) handleStatus() {
-	p.computersMux.RLock()
-	defer p.computersMux.RUnlock()
-	
-	if len(p.computers) == 0 {
-		p.sendMessage("Нет зарегистрированных компьютеров")
+// handleStatus shows brief status of all computers, e.g. /status tag:office
+func (p *Poller) handleStatus(chatID string, loc *i18n.Localizer, args []string) {
+	filter := registry.ParseFilter(args)
+	computers := p.computers.Find(filter)
+	if len(computers) == 0 {
+		if filter.Empty() {
+			p.sendMessage(chatID, loc.T("bot.no_registered"))
+		} else {
+			p.sendMessage(chatID, loc.T("bot.no_match"))
+		}
 		return
 	}
 	
-	status := "📊 <b>Статус компьютеров:</b>\n\n"
+	status := loc.T("bot.status_title") + "\n\n"
 	
-	for _, comp := range p.computers {
-		elapsed := time.Since(comp.LastSeen)
+	now := time.Now()
+	for _, comp := range computers {
+		elapsed := now.Sub(comp.LastSeen)
 		statusIcon := "✅"
-		statusText := "Online"
+		statusText := loc.T("bot.online")
 		
-		if elapsed > 10*time.Minute {
+		if !comp.Online(now) {
 			statusIcon = "❌"
-			statusText = "Offline"
+			statusText = loc.T("bot.offline")
 		}
 		
-		status += fmt.Sprintf("%s <b>%s</b> - %s\n", statusIcon, comp.Name, statusText)
-		status += fmt.Sprintf("   Последняя активность: %v назад\n\n", elapsed.Round(time.Minute))
+		status += fmt.Sprintf("%s <b>%s</b> - %s\n", statusIcon, html.EscapeString(comp.Name), statusText)
+		if comp.Summary != nil {
+			status += "   " + loc.T("bot.summary", comp.Summary.CPU, comp.Summary.Memory, comp.Summary.Disk,
+				loc.Duration(time.Duration(comp.Summary.Uptime)*time.Second)) + "\n"
+		}
+		status += "   " + loc.T("bot.last_seen", loc.DateTime(comp.LastSeen), loc.Duration(elapsed)) + "\n\n"
 	}
 	
-	p.sendMessage(status)
+	p.sendMessage(chatID, status)
 }
 
-// handleHelp shows help message
-func (p *Poller) handleHelp() {
-	help := `📖 <b>Доступные команды:</b>
+// handleComputers lists known computers with their ID, group, tags and owner
+func (p *Poller) handleComputers(chatID string, loc *i18n.Localizer, args []string) {
+	filter := registry.ParseFilter(args)
+	computers := p.computers.Find(filter)
+	if len(computers) == 0 {
+		if filter.Empty() {
+			p.sendMessage(chatID, loc.T("bot.no_registered"))
+		} else {
+			p.sendMessage(chatID, loc.T("bot.no_match"))
+		}
+		return
+	}
+	
+	text := loc.T("bot.computers_title") + "\n\n"
+	for _, comp := range computers {
+		text += fmt.Sprintf("<b>%s</b> <code>%s</code>\n", html.EscapeString(comp.Name), html.EscapeString(comp.ID))
+		if comp.Group != "" {
+			text += "   " + loc.T("bot.computer_group", html.EscapeString(comp.Group)) + "\n"
+		}
+		if len(comp.Tags) > 0 {
+			text += "   " + loc.T("bot.computer_tags", html.EscapeString(strings.Join(comp.Tags, ", "))) + "\n"
+		}
+		if comp.Owner != "" {
+			text += "   " + loc.T("bot.computer_owner", html.EscapeString(comp.Owner)) + "\n"
+		}
+		text += "   " + loc.T("bot.computer_seen", loc.DateTime(comp.LastSeen)) + "\n\n"
+	}
+	
+	p.sendMessage(chatID, text)
+}
+
+// handleRename changes the display name of a computer: /rename <id> <name>
+func (p *Poller) handleRename(chatID string, loc *i18n.Localizer, args []string) {
+	if len(args) < 2 {
+		p.sendMessage(chatID, loc.T("bot.rename_usage"))
+		return
+	}
+	
+	id, name := args[0], strings.Join(args[1:], " ")
+	if err := p.computers.Rename(id, name); err != nil {
+		p.sendMessage(chatID, loc.T("bot.unknown_computer", html.EscapeString(id)))
+		return
+	}
+	
+	p.sendMessage(chatID, loc.T("bot.renamed", html.EscapeString(id), html.EscapeString(name)))
+}
 
-/info - Получить подробную информацию о компьютере
-/status - Краткий статус всех компьютеров  
-/help - Показать эту справку
+// handleForget removes a computer from the registry: /forget <id>
+func (p *Poller) handleForget(chatID string, loc *i18n.Localizer, args []string) {
+	if len(args) != 1 {
+		p.sendMessage(chatID, loc.T("bot.forget_usage"))
+		return
+	}
+	
+	id := args[0]
+	comp, known := p.computers.Get(id)
+	if !known {
+		p.sendMessage(chatID, loc.T("bot.unknown_computer", html.EscapeString(id)))
+		return
+	}
+	if comp.Local {
+		p.sendMessage(chatID, loc.T("bot.forget_local"))
+		return
+	}
+	
+	if err := p.computers.Forget(id); err != nil {
+		p.sendMessage(chatID, loc.T("bot.forget_error", html.EscapeString(err.Error())))
+		return
+	}
+	
+	p.sendMessage(chatID, loc.T("bot.forgotten", html.EscapeString(comp.Name)))
+}
+
+// handleChart sends CPU, memory and disk graphs for the requested period
+func (p *Poller) handleChart(chatID string, loc *i18n.Localizer, args []string) {
+	if p.store == nil {
+		p.sendMessage(chatID, loc.T("bot.no_history"))
+		return
+	}
+	
+	periodArg := defaultPeriod
+	if len(args) > 0 {
+		periodArg = args[0]
+	}
+	
+	period, err := config.ParseDuration(periodArg)
+	if err != nil || period <= 0 {
+		p.sendMessage(chatID, loc.T("bot.bad_period"))
+		return
+	}
+	
+	to := time.Now()
+	from := to.Add(-period)
+	
+	samples, err := p.store.Query(from, to)
+	if err != nil {
+		p.sendMessage(chatID, loc.T("bot.history_error", html.EscapeString(err.Error())))
+		return
+	}
+	
+	image, err := chart.RenderHistory(samples, from, to)
+	if err != nil {
+		p.sendMessage(chatID, loc.T("bot.not_enough_data", periodArg))
+		return
+	}
+	
+	caption := loc.T("bot.chart_caption", html.EscapeString(p.cfg.ComputerName), periodArg)
+	if err := p.client.SendPhoto(context.Background(), chatID, caption, image); err != nil {
+		log.Printf("Ошибка отправки графика: %v", err)
+	}
+}
 
-💡 <b>Как использовать:</b>
-1. Отправьте /info
-2. Выберите компьютер из списка
-3. Получите полный отчет
+// handleLang shows or changes the language of the chat
+func (p *Poller) handleLang(chatID string, loc *i18n.Localizer, args []string) {
+	available := strings.Join(i18n.Languages(), ", ")
+	
+	if len(args) == 0 {
+		p.sendMessage(chatID, loc.T("bot.lang_current", loc.Lang(), available))
+		return
+	}
+	
+	lang := strings.ToLower(args[0])
+	if !i18n.Supported(lang) {
+		p.sendMessage(chatID, loc.T("bot.lang_unknown", html.EscapeString(lang), available))
+		return
+	}
+	
+	if err := i18n.SetChatLanguage(chatID, lang); err != nil {
+		p.sendMessage(chatID, loc.T("bot.lang_error", html.EscapeString(err.Error())))
+		return
+	}
+	
+	p.sendMessage(chatID, i18n.New(lang).T("bot.lang_set"))
+}
 
-Автоматические отчеты отправляются ежедневно в 08:00`
+// handleHelp shows help message
+func (p *Poller) handleHelp(chatID string, loc *i18n.Localizer) {
+	p.sendMessage(chatID, loc.T("bot.help", p.describeSchedules(loc)))
+}
 
-	p.sendMessage(help)
+// describeSchedules lists when scheduled reports are sent
+func (p *Poller) describeSchedules(loc *i18n.Localizer) string {
+	var parts []string
+	for _, schedule := range p.cfg.ReportSchedules() {
+		var part string
+		switch {
+		case schedule.Cron != "":
+			part = loc.T("bot.schedule_cron", html.EscapeString(schedule.Cron))
+		case schedule.Every != "":
+			part = loc.T("bot.schedule_every", loc.Duration(schedule.Interval()))
+		default:
+			part = loc.T("bot.schedule_at", html.EscapeString(schedule.At))
+		}
+		if profile := schedule.ProfileName(); profile != config.ProfileFull {
+			part += " (" + profile + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; ")
 }
 
 // handleCallback processes button presses
 func (p *Poller) handleCallback(query *CallbackQuery) {
-	computerID := query.Data
-	
-	log.Printf("Запрос информации о компьютере: %s", computerID)
-	
 	// Answer callback query first
 	p.answerCallbackQuery(query.ID)
 	
+	// Buttons pressed in a private chat without the message fall back to the user's chat
+	var chat int64
+	if query.From != nil {
+		chat = query.From.ID
+	}
+	if query.Message != nil && query.Message.Chat != nil {
+		chat = query.Message.Chat.ID
+	}
+	chatID := strconv.FormatInt(chat, 10)
+	loc := i18n.ForChat(chatID, p.cfg.Language)
+	
+	// Process confirmations carry their own prefix; other buttons are computer IDs
+	if action, isKill := strings.CutPrefix(query.Data, killCallbackPrefix); isKill {
+		if p.authorize(query.From, chat, "/kill", commandRoles["/kill"], loc) {
+			p.handleKillCallback(chatID, loc, action)
+		}
+		return
+	}
+	if action, isService := strings.CutPrefix(query.Data, serviceCallbackPrefix); isService {
+		if p.authorize(query.From, chat, "/restart", commandRoles["/restart"], loc) {
+			p.handleServiceCallback(chatID, loc, action)
+		}
+		return
+	}
+	
+	if !p.authorize(query.From, chat, "/info", commandRoles["/info"], loc) {
+		return
+	}
+	
+	computerID := query.Data
+	log.Printf("Запрос информации о компьютере: %s", computerID)
+	
 	// Check if this is our computer
 	if computerID == p.cfg.ComputerID {
 		// Send report
-		report, err :=telegram.CreateReport(p.cfg.ComputerName)
+		message, err := report.HTML(loc).Render(report.Collect(p.cfg, p.store))
 		if err != nil {
-			p.sendMessage(fmt.Sprintf("Ошибка создания отчета: %v", err))
+			p.sendMessage(chatID, loc.T("bot.report_error", html.EscapeString(err.Error())))
 			return
 		}
 		
-		p.sendMessage(report)
+		p.sendMessage(chatID, message)
 		p.UpdateLastSeen()
+		return
+	}
+	
+	// Route the request to the agent through the hub
+	comp, known := p.computers.Get(computerID)
+	if p.hub == nil || !known {
+		p.sendMessage(chatID, loc.T("bot.no_computers"))
+		return
+	}
+	
+	if err := p.hub.RequestReport(computerID, chatID, loc); err != nil {
+		p.sendMessage(chatID, loc.T("bot.request_error", html.EscapeString(err.Error())))
+		return
+	}
+	
+	if comp.Online(time.Now()) {
+		p.sendMessage(chatID, loc.T("bot.request_sent", html.EscapeString(comp.Name)))
+	}
+}
+
+// senderName returns a printable name of a message sender
+func senderName(from *User) string {
+	if from == nil {
+		return "?"
+	}
+	if from.Username != "" {
+		return from.Username
 	}
+	return strconv.FormatInt(from.ID, 10)
 }
 
 // createComputerKeyboard creates inline keyboard with computer buttons
-func (p *Poller) createComputerKeyboard() InlineKeyboardMarkup {
+func (p *Poller) createComputerKeyboard(computers []registry.Computer) InlineKeyboardMarkup {
 	var buttons [][]InlineKeyboardButton
 	var row []InlineKeyboardButton
 	
 	i := 0
-	for _, comp := range p.computers {
+	for _, comp := range computers {
 		button := InlineKeyboardButton{
 			Text:         comp.Name,
 			CallbackData: comp.ID,
@@ -310,44 +628,27 @@ func (p *Poller) createComputerKeyboard() InlineKeyboardMarkup {
 	return InlineKeyboardMarkup{InlineKeyboard: buttons}
 }
 
-// sendMessage sends a text message
-func (p *Poller) sendMessage(text string) error {
-	return telegram.SendMessage(p.token, p.chatID, text)
-}
-
-// sendKeyboard sends a message with inline keyboard
-func (p *Poller) sendKeyboard(text string, keyboard InlineKeyboardMarkup) error {
-	url := fmt.Sprintf(telegramAPIURL+"/sendMessage", p.token)
-	
-	payload := map[string]interface{}{
-		"chat_id":      p.chatID,
-		"text":         text,
-		"parse_mode":   "HTML",
-		"reply_markup": keyboard,
-	}
-	
-	jsonPayload, err := json.Marshal(payload)
+// sendMessage sends a text message to a chat
+func (p *Poller) sendMessage(chatID, text string) error {
+	err := p.client.SendMessage(context.Background(), chatID, text)
 	if err != nil {
-		return err
+		log.Printf("Ошибка отправки сообщения в чат %s: %v", chatID, err)
 	}
-	
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonPayload))
+	return err
+}
+
+// sendKeyboard sends a message with inline keyboard to a chat
+func (p *Poller) sendKeyboard(chatID, text string, keyboard InlineKeyboardMarkup) error {
+	err := p.client.SendMessageMarkup(context.Background(), chatID, text, keyboard)
 	if err != nil {
-		return err
+		log.Printf("Ошибка отправки сообщения в чат %s: %v", chatID, err)
 	}
-	defer resp.Body.Close()
-	
-	return nil
+	return err
 }
 
 // answerCallbackQuery answers a callback query
 func (p *Poller) answerCallbackQuery(queryID string) {
-	url := fmt.Sprintf(telegramAPIURL+"/answerCallbackQuery", p.token)
-	
-	payload := map[string]string{
-		"callback_query_id": queryID,
+	if err := p.client.AnswerCallbackQuery(context.Background(), queryID); err != nil {
+		log.Printf("Ошибка ответа на нажатие кнопки: %v", err)
 	}
-	
-	jsonPayload, _ := json.Marshal(payload)
-	http.Post(url, "application/json", bytes.NewBuffer(jsonPayload))
 }
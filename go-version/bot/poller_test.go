@@ -0,0 +1,245 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"system-monitor/storage"
+	"system-monitor/telegram"
+	"testing"
+	"time"
+)
+
+// call is one Bot API request received by the stub
+type call struct {
+	method string
+	fields map[string]string
+}
+
+// apiStub is a Bot API server that accepts every request and records it
+type apiStub struct {
+	mu    sync.Mutex
+	calls []call
+}
+
+func (s *apiStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	fields := make(map[string]string)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		r.ParseMultipartForm(1 << 20)
+		for name, values := range r.MultipartForm.Value {
+			fields[name] = values[0]
+		}
+	} else {
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+		for name, value := range payload {
+			fields[name] = fmt.Sprint(value)
+		}
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, call{method: path.Base(r.URL.Path), fields: fields})
+	s.mu.Unlock()
+
+	w.Write([]byte(`{"ok": true, "result": true}`))
+}
+
+// captions returns the captions of the files sent so far
+func (s *apiStub) captions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var captions []string
+	for _, c := range s.calls {
+		if _, ok := c.fields["caption"]; ok {
+			captions = append(captions, c.fields["caption"])
+		}
+	}
+	return captions
+}
+
+// texts returns the texts of the messages sent so far
+func (s *apiStub) texts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var texts []string
+	for _, c := range s.calls {
+		if c.method == "sendMessage" {
+			texts = append(texts, c.fields["text"])
+		}
+	}
+	return texts
+}
+
+// newTestPoller creates a poller talking to a stub Bot API; edit adjusts
+// the config before the poller is created
+func newTestPoller(t *testing.T, edit func(cfg *config.Config)) (*Poller, *apiStub) {
+	t.Helper()
+
+	stub := &apiStub{}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	dir := t.TempDir()
+	cfg := &config.Config{
+		ComputerName:   "host",
+		TelegramToken:  "test",
+		TelegramAPIURL: server.URL,
+		ChatID:         "-1001234567890",
+		Language:       "en",
+		LogFile:        filepath.Join(dir, "monitor.log"),
+		Access:         config.Access{AuditLog: filepath.Join(dir, "access_denied.log")},
+	}
+	if edit != nil {
+		edit(cfg)
+	}
+
+	return NewPoller(cfg, nil, nil, nil, telegram.NewClient(cfg)), stub
+}
+
+// command builds a message sent by a user in a chat
+func command(userID, chatID int64, text string) *Message {
+	return &Message{
+		From: &User{ID: userID, FirstName: "test"},
+		Chat: &Chat{ID: chatID},
+		Text: text,
+	}
+}
+
+func TestCommandRoles(t *testing.T) {
+	const group = -1001234567890
+
+	tests := []struct {
+		name   string
+		access config.Access
+		chatID string
+		userID int64
+		text   string
+		// want is a substring of the only reply
+		want string
+	}{
+		{
+			name:   "group member without grant may not kill",
+			userID: 42,
+			text:   "/kill 1",
+			want:   "Not enough rights for /kill (role admin required)",
+		},
+		{
+			name:   "group member without grant may not restart",
+			userID: 42,
+			text:   "/restart nginx",
+			want:   "Not enough rights for /restart (role operator required)",
+		},
+		{
+			name:   "group member without grant may not inspect processes",
+			userID: 42,
+			text:   "/proc 1",
+			want:   "Not enough rights for /proc",
+		},
+		{
+			name:   "group member may ask for help",
+			userID: 42,
+			text:   "/help",
+			want:   "/status",
+		},
+		{
+			name:   "admin user may kill",
+			access: config.Access{Users: map[string]string{"42": config.RoleAdmin}},
+			userID: 42,
+			text:   "/kill",
+			want:   "Usage: /kill &lt;pid&gt;",
+		},
+		{
+			name:   "private chat owner may kill",
+			chatID: "42",
+			userID: 42,
+			text:   "/kill",
+			want:   "Usage: /kill &lt;pid&gt;",
+		},
+		{
+			name:   "stranger is denied",
+			access: config.Access{Users: map[string]string{"42": config.RoleAdmin}},
+			userID: 7,
+			text:   "/status",
+			want:   "Access denied. Your ID: <code>7</code>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, stub := newTestPoller(t, func(cfg *config.Config) {
+				cfg.Access.Users = tt.access.Users
+				if tt.chatID != "" {
+					cfg.ChatID = tt.chatID
+				}
+			})
+
+			chatID := int64(group)
+			if tt.chatID != "" {
+				chatID = tt.userID
+			}
+			p.handleCommand(context.Background(), command(tt.userID, chatID, tt.text))
+
+			texts := stub.texts()
+			if len(texts) != 1 {
+				t.Fatalf("sent %d messages, want 1: %q", len(texts), texts)
+			}
+			if !strings.Contains(texts[0], tt.want) {
+				t.Errorf("reply %q does not contain %q", texts[0], tt.want)
+			}
+		})
+	}
+}
+
+func TestChartCaptionEscapesName(t *testing.T) {
+	p, stub := newTestPoller(t, func(cfg *config.Config) {
+		cfg.ComputerName = "<db & co>"
+		cfg.History.Dir = t.TempDir()
+	})
+
+	store, err := storage.Open(p.cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for i := 3; i > 0; i-- {
+		sample := storage.Sample{Time: now.Add(-time.Duration(i) * 10 * time.Minute), CPU: 10, Memory: 20}
+		if err := store.Append(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	p.store = store
+
+	p.handleChart("-100", i18n.New("en"), []string{"1h"})
+
+	captions := stub.captions()
+	if len(captions) != 1 {
+		t.Fatalf("sent %d files, want 1 (messages %q)", len(captions), stub.texts())
+	}
+	if want := "<b>&lt;db &amp; co&gt;</b>"; !strings.Contains(captions[0], want) {
+		t.Errorf("caption %q does not contain %q", captions[0], want)
+	}
+}
+
+func TestLangEscapesUnknownLanguage(t *testing.T) {
+	p, stub := newTestPoller(t, nil)
+
+	p.handleCommand(context.Background(), command(42, -1001234567890, "/lang <b"))
+
+	texts := stub.texts()
+	if len(texts) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(texts))
+	}
+	if !strings.Contains(texts[0], "Unknown language &lt;b.") {
+		t.Errorf("reply %q does not escape the language", texts[0])
+	}
+}
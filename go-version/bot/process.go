@@ -0,0 +1,221 @@
+package bot
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"system-monitor/i18n"
+	"system-monitor/monitor"
+	"time"
+)
+
+const (
+	// defaultTopLimit is how many processes /top lists without a count
+	defaultTopLimit = 10
+	// maxTopLimit keeps /top within a single Telegram message
+	maxTopLimit = 40
+	// maxOpenFiles is how many open files /proc lists
+	maxOpenFiles = 10
+	// maxCmdline is how many characters of a command line are shown
+	maxCmdline = 500
+	// killCallbackPrefix marks /kill confirmation buttons
+	killCallbackPrefix = "kill:"
+	// confirmTTL is how long a confirmation button stays valid
+	confirmTTL = 5 * time.Minute
+)
+
+// handleTop lists processes of this computer, e.g. /top mem 20 chrome
+func (p *Poller) handleTop(chatID string, loc *i18n.Localizer, args []string) {
+	sortBy := monitor.SortByCPU
+	limit := defaultTopLimit
+	filter := ""
+
+	for _, arg := range args {
+		switch strings.ToLower(arg) {
+		case "cpu":
+			sortBy = monitor.SortByCPU
+		case "mem", "memory", "ram":
+			sortBy = monitor.SortByMemory
+		case "name":
+			sortBy = monitor.SortByName
+		default:
+			if n, err := strconv.Atoi(arg); err == nil && n > 0 {
+				limit = min(n, maxTopLimit)
+			} else {
+				filter = arg
+			}
+		}
+	}
+
+	procs, err := monitor.ListProcesses()
+	if err != nil {
+		p.sendMessage(chatID, loc.T("bot.proc_error", html.EscapeString(err.Error())))
+		return
+	}
+
+	if filter != "" {
+		procs = monitor.FilterProcesses(procs, filter)
+	}
+	if len(procs) == 0 {
+		p.sendMessage(chatID, loc.T("bot.top_empty"))
+		return
+	}
+
+	monitor.SortProcesses(procs, sortBy)
+	total := len(procs)
+	if len(procs) > limit {
+		procs = procs[:limit]
+	}
+
+	text := loc.T("bot.top_title", html.EscapeString(p.cfg.ComputerName), len(procs), total) + "\n\n"
+	for _, proc := range procs {
+		text += loc.T("bot.top_line", proc.PID, html.EscapeString(proc.Name), proc.CPUPercent, proc.MemoryMB) + "\n"
+	}
+	text += "\n" + loc.T("bot.top_hint")
+
+	p.sendMessage(chatID, text)
+}
+
+// handleProc shows details of a process: /proc <pid>
+func (p *Poller) handleProc(chatID string, loc *i18n.Localizer, args []string) {
+	pid, ok := parsePID(args)
+	if !ok {
+		p.sendMessage(chatID, loc.T("bot.proc_usage"))
+		return
+	}
+
+	details, err := monitor.GetProcessDetails(pid)
+	if err != nil {
+		p.sendMessage(chatID, loc.T("bot.proc_not_found", pid))
+		return
+	}
+
+	p.sendMessage(chatID, formatProcess(loc, details))
+}
+
+// handleKill asks for confirmation before stopping a process: /kill <pid>
+func (p *Poller) handleKill(chatID string, loc *i18n.Localizer, args []string) {
+	pid, ok := parsePID(args)
+	if !ok {
+		p.sendMessage(chatID, loc.T("bot.kill_usage"))
+		return
+	}
+
+	details, err := monitor.GetProcessDetails(pid)
+	if err != nil {
+		p.sendMessage(chatID, loc.T("bot.proc_not_found", pid))
+		return
+	}
+
+	// The start time guards against the PID being reused before the
+	// button is pressed; the issue time lets stale buttons expire
+	target := fmt.Sprintf("%d:%d:%d", pid, details.CreateTime.UnixMilli(), time.Now().Unix())
+	keyboard := InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+		{
+			{Text: loc.T("bot.kill_terminate"), CallbackData: killCallbackPrefix + "term:" + target},
+			{Text: loc.T("bot.kill_force"), CallbackData: killCallbackPrefix + "kill:" + target},
+		},
+		{
+			{Text: loc.T("bot.kill_cancel"), CallbackData: killCallbackPrefix + "cancel"},
+		},
+	}}
+
+	text := loc.T("bot.kill_confirm") + "\n\n" + formatProcess(loc, details)
+	p.sendKeyboard(chatID, text, keyboard)
+}
+
+// handleKillCallback carries out a confirmed /kill; action is the callback
+// data without the prefix: "cancel" or "<term|kill>:<pid>:<created>:<issued>"
+func (p *Poller) handleKillCallback(chatID string, loc *i18n.Localizer, action string) {
+	if action == "cancel" {
+		p.sendMessage(chatID, loc.T("bot.kill_cancelled"))
+		return
+	}
+
+	parts := strings.Split(action, ":")
+	if len(parts) != 4 {
+		return
+	}
+
+	pid, err1 := strconv.ParseInt(parts[1], 10, 32)
+	created, err2 := strconv.ParseInt(parts[2], 10, 64)
+	issued, err3 := strconv.ParseInt(parts[3], 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return
+	}
+
+	if time.Since(time.Unix(issued, 0)) > confirmTTL {
+		p.sendMessage(chatID, loc.T("bot.kill_expired"))
+		return
+	}
+
+	force := parts[0] == "kill"
+	log.Printf("Остановка процесса %d (force=%v) по запросу из чата %s", pid, force, chatID)
+
+	if err := monitor.StopProcess(int32(pid), time.UnixMilli(created), force); err != nil {
+		log.Printf("Ошибка остановки процесса %d: %v", pid, err)
+		p.sendMessage(chatID, loc.T("bot.kill_error", pid, html.EscapeString(err.Error())))
+		return
+	}
+
+	if force {
+		p.sendMessage(chatID, loc.T("bot.killed", pid))
+	} else {
+		p.sendMessage(chatID, loc.T("bot.terminated", pid))
+	}
+}
+
+// formatProcess renders process details as an HTML message
+func formatProcess(loc *i18n.Localizer, d *monitor.ProcessDetails) string {
+	text := loc.T("bot.proc_title", html.EscapeString(d.Name), d.PID) + "\n"
+	text += loc.T("bot.proc_usage_line", d.CPUPercent, d.MemoryMB, d.MemoryPercent) + "\n"
+
+	if d.Username != "" {
+		text += loc.T("bot.proc_user", html.EscapeString(d.Username)) + "\n"
+	}
+	if !d.CreateTime.IsZero() {
+		text += loc.T("bot.proc_started", loc.DateTime(d.CreateTime)) + "\n"
+	}
+	text += loc.T("bot.proc_threads", d.Threads, d.PPID) + "\n"
+	if d.Status != "" {
+		text += loc.T("bot.proc_status", html.EscapeString(d.Status)) + "\n"
+	}
+	if d.Exe != "" {
+		text += loc.T("bot.proc_exe", html.EscapeString(d.Exe)) + "\n"
+	}
+	if d.Cmdline != "" {
+		cmdline := []rune(d.Cmdline)
+		if len(cmdline) > maxCmdline {
+			cmdline = append(cmdline[:maxCmdline], '…')
+		}
+		text += loc.T("bot.proc_cmdline", html.EscapeString(string(cmdline))) + "\n"
+	}
+
+	if len(d.OpenFiles) > 0 {
+		text += "\n" + loc.T("bot.proc_files", len(d.OpenFiles)) + "\n"
+		for i, path := range d.OpenFiles {
+			if i == maxOpenFiles {
+				text += loc.T("bot.proc_files_more", len(d.OpenFiles)-maxOpenFiles) + "\n"
+				break
+			}
+			text += "• <code>" + html.EscapeString(path) + "</code>\n"
+		}
+	}
+
+	return text
+}
+
+// parsePID reads a process ID from the only command argument
+func parsePID(args []string) (int32, bool) {
+	if len(args) != 1 {
+		return 0, false
+	}
+
+	pid, err := strconv.ParseInt(args[0], 10, 32)
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return int32(pid), true
+}
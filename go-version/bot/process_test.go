@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"system-monitor/i18n"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// startSleeper starts a child process for /kill to stop and returns it with
+// its start time in milliseconds
+func startSleeper(t *testing.T) (*exec.Cmd, int64) {
+	t.Helper()
+
+	path, err := exec.LookPath("sleep")
+	if err != nil {
+		t.Skip("sleep is not available")
+	}
+	cmd := exec.Command(path, "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	proc, err := process.NewProcess(int32(cmd.Process.Pid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := proc.CreateTime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cmd, created
+}
+
+func TestKillCallback(t *testing.T) {
+	cmd, created := startSleeper(t)
+	pid := cmd.Process.Pid
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name   string
+		action string
+		want   string
+	}{
+		{"cancel", "cancel", "Process stop cancelled"},
+		{"expired", fmt.Sprintf("term:%d:%d:%d", pid, created, now-int64(confirmTTL.Seconds())-60), "The confirmation has expired"},
+		{"pid reused", fmt.Sprintf("term:%d:%d:%d", pid, created+1000, now), "has been replaced by another process"},
+		{"terminate", fmt.Sprintf("term:%d:%d:%d", pid, created, now), fmt.Sprintf("Process %d was asked to terminate", pid)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, stub := newTestPoller(t, nil)
+			p.handleKillCallback("-100", i18n.New("en"), tt.action)
+
+			texts := stub.texts()
+			if len(texts) != 1 || !strings.Contains(texts[0], tt.want) {
+				t.Errorf("replies %q, want one containing %q", texts, tt.want)
+			}
+		})
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		t.Error("the process was not stopped")
+	}
+}
+
+func TestKillAsksForConfirmation(t *testing.T) {
+	cmd, _ := startSleeper(t)
+
+	p, stub := newTestPoller(t, nil)
+	p.handleKill("-100", i18n.New("en"), []string{fmt.Sprint(cmd.Process.Pid)})
+
+	texts := stub.texts()
+	if len(texts) != 1 || !strings.Contains(texts[0], "Stop this process?") || !strings.Contains(texts[0], "sleep") {
+		t.Fatalf("replies %q, want a confirmation naming the process", texts)
+	}
+}
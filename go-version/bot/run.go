@@ -0,0 +1,196 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"os/exec"
+	"strconv"
+	"strings"
+	"system-monitor/access"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"time"
+)
+
+const (
+	// maxRunOutput caps how much command output is kept
+	maxRunOutput = 1 << 20
+	// maxInlineOutput is the longest output sent as a message; longer
+	// output is sent as a document
+	maxInlineOutput = 3000
+	// runWaitDelay bounds waiting for output of processes left behind by
+	// a killed command
+	runWaitDelay = 5 * time.Second
+)
+
+// limitedBuffer keeps the first max bytes written and drops the rest
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+// Write implements io.Writer
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+// handleRun runs a named command from the config: /run <name>. Without a
+// name it lists the commands the sender may run. The command is killed when
+// ctx is done.
+func (p *Poller) handleRun(ctx context.Context, msg *Message, loc *i18n.Localizer, args []string) {
+	chatID := strconv.FormatInt(msg.Chat.ID, 10)
+
+	if len(args) == 0 {
+		p.listCommands(chatID, loc, p.policy.RoleOf(senderID(msg.From), msg.Chat.ID))
+		return
+	}
+
+	command, found := p.findCommand(args[0])
+	if !found {
+		p.sendMessage(chatID, loc.T("bot.run_unknown", html.EscapeString(args[0])))
+		return
+	}
+
+	if !p.authorize(msg.From, msg.Chat.ID, "/run "+command.Name, commandRole(command), loc) {
+		return
+	}
+
+	// Commands may run for minutes; keep answering other updates meanwhile
+	p.commands.Add(1)
+	go func() {
+		defer p.commands.Done()
+		p.runCommand(ctx, chatID, loc, command, senderName(msg.From))
+	}()
+}
+
+// listCommands shows the commands a role may run
+func (p *Poller) listCommands(chatID string, loc *i18n.Localizer, role access.Role) {
+	text := loc.T("bot.run_title") + "\n\n"
+	count := 0
+	for _, command := range p.cfg.Commands {
+		if role < commandRole(command) {
+			continue
+		}
+		text += "<code>/run " + html.EscapeString(command.Name) + "</code>"
+		if command.Description != "" {
+			text += " - " + html.EscapeString(command.Description)
+		}
+		text += "\n"
+		count++
+	}
+
+	if count == 0 {
+		p.sendMessage(chatID, loc.T("bot.run_none"))
+		return
+	}
+	p.sendMessage(chatID, text)
+}
+
+// runCommand executes a command and sends its output, exit code and
+// duration. A command interrupted by the service stopping is only logged.
+func (p *Poller) runCommand(parent context.Context, chatID string, loc *i18n.Localizer, command config.Command, user string) {
+	timeout := command.TimeoutDuration()
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	output := &limitedBuffer{max: maxRunOutput}
+	cmd := exec.CommandContext(ctx, command.Argv[0], command.Argv[1:]...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.WaitDelay = runWaitDelay
+
+	log.Printf("Запуск команды %s по запросу %s (чат %s)", command.Name, user, chatID)
+
+	started := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(started).Round(time.Millisecond)
+
+	exitCode := -1
+	if cmd.ProcessState != nil {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+
+	log.Printf("Команда %s завершена: код %d, длительность %v, ошибка: %v", command.Name, exitCode, elapsed, err)
+
+	if parent.Err() != nil {
+		log.Printf("Команда %s прервана остановкой сервиса, результат не отправлен", command.Name)
+		return
+	}
+
+	var exitErr *exec.ExitError
+	var header string
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		header = loc.T("bot.run_timeout", html.EscapeString(command.Name), loc.Duration(timeout))
+	case err != nil && !errors.As(err, &exitErr):
+		p.sendMessage(chatID, loc.T("bot.run_error", html.EscapeString(command.Name), html.EscapeString(err.Error())))
+		return
+	default:
+		header = loc.T("bot.run_result", html.EscapeString(command.Name), exitCode, elapsed.String())
+	}
+
+	text := strings.TrimRight(output.buf.String(), "\n")
+	if output.truncated {
+		text += "\n" + loc.T("bot.run_truncated", maxRunOutput/1024)
+	}
+
+	if text == "" {
+		p.sendMessage(chatID, header+"\n\n"+loc.T("bot.run_no_output"))
+		return
+	}
+
+	if len([]rune(text)) <= maxInlineOutput {
+		p.sendMessage(chatID, header+"\n\n<pre>"+html.EscapeString(text)+"</pre>")
+		return
+	}
+
+	filename := fmt.Sprintf("%s-%s.txt", command.Name, started.Format("20060102-150405"))
+	if err := p.client.SendDocument(context.Background(), chatID, header, filename, []byte(text)); err != nil {
+		log.Printf("Ошибка отправки вывода команды %s: %v", command.Name, err)
+	}
+}
+
+// findCommand looks up a configured command by name
+func (p *Poller) findCommand(name string) (config.Command, bool) {
+	for _, command := range p.cfg.Commands {
+		if command.Name == name {
+			return command, true
+		}
+	}
+	return config.Command{}, false
+}
+
+// commandRole returns the lowest role allowed to run a command
+func commandRole(command config.Command) access.Role {
+	if len(command.Roles) == 0 {
+		return access.RoleOperator
+	}
+
+	lowest := access.RoleAdmin
+	for _, name := range command.Roles {
+		if role, ok := access.ParseRole(name); ok && role < lowest {
+			lowest = role
+		}
+	}
+	return lowest
+}
+
+// senderID returns the user ID of a message sender, or 0 when unknown
+func senderID(from *User) int64 {
+	if from == nil {
+		return 0
+	}
+	return from.ID
+}
@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"system-monitor/access"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	commands := []config.Command{
+		{Name: "hello", Argv: []string{"sh", "-c", "echo '<hi>'; echo oops >&2; exit 3"}},
+		{Name: "quiet", Argv: []string{"true"}, Roles: []string{config.RoleViewer}},
+		{Name: "slow", Argv: []string{"sleep", "10"}, Timeout: "100ms"},
+		{Name: "long", Argv: []string{"sh", "-c", "i=0; while [ $i -lt 400 ]; do echo 0123456789; i=$((i+1)); done"}},
+		{Name: "missing", Argv: []string{"/nonexistent/command"}},
+		{Name: "reboot", Argv: []string{"true"}, Roles: []string{config.RoleAdmin}, Description: "Reboot <now>"},
+	}
+
+	tests := []struct {
+		name string
+		role string
+		text string
+		// method is the Bot API method of the only reply
+		method string
+		// want is a substring of the reply text or caption
+		want string
+	}{
+		{"output and exit code", config.RoleOperator, "/run hello", "sendMessage", "<b>hello</b>: exit code 3"},
+		{"output is escaped", config.RoleOperator, "/run hello", "sendMessage", "<pre>&lt;hi&gt;\noops</pre>"},
+		{"no output", config.RoleViewer, "/run quiet", "sendMessage", "(no output)"},
+		{"timeout", config.RoleOperator, "/run slow", "sendMessage", "<b>slow</b> was stopped after the timeout"},
+		{"long output as document", config.RoleOperator, "/run long", "sendDocument", "<b>long</b>: exit code 0"},
+		{"start error", config.RoleOperator, "/run missing", "sendMessage", "Failed to start missing"},
+		{"unknown", config.RoleOperator, "/run rm -rf /", "sendMessage", "Command rm is not configured"},
+		{"role required", config.RoleOperator, "/run reboot", "sendMessage", "Not enough rights for /run reboot (role admin required)"},
+		{"viewer needs a grant", config.RoleViewer, "/run hello", "sendMessage", "Not enough rights for /run hello"},
+		{"list", config.RoleViewer, "/run", "sendMessage", "<code>/run quiet</code>"},
+		{"list for operator", config.RoleOperator, "/run", "sendMessage", "<code>/run slow</code>"},
+		{"list for admin", config.RoleAdmin, "/run", "sendMessage", "<code>/run reboot</code> - Reboot &lt;now&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, stub := newTestPoller(t, func(cfg *config.Config) {
+				cfg.Commands = commands
+				cfg.Access.Users = map[string]string{"42": tt.role}
+			})
+
+			p.handleCommand(context.Background(), command(42, -1001234567890, tt.text))
+			p.commands.Wait()
+
+			stub.mu.Lock()
+			defer stub.mu.Unlock()
+			if len(stub.calls) != 1 {
+				t.Fatalf("%d replies, want 1: %+v", len(stub.calls), stub.calls)
+			}
+			reply := stub.calls[0]
+			if reply.method != tt.method {
+				t.Errorf("reply sent with %s, want %s", reply.method, tt.method)
+			}
+			if text := reply.fields["text"] + reply.fields["caption"]; !strings.Contains(text, tt.want) {
+				t.Errorf("reply %q does not contain %q", text, tt.want)
+			}
+		})
+	}
+}
+
+func TestListHidesCommandsAboveRole(t *testing.T) {
+	p, stub := newTestPoller(t, func(cfg *config.Config) {
+		cfg.Commands = []config.Command{
+			{Name: "uptime", Argv: []string{"uptime"}, Roles: []string{config.RoleViewer}},
+			{Name: "reboot", Argv: []string{"reboot"}, Roles: []string{config.RoleAdmin}},
+		}
+	})
+
+	p.listCommands("42", i18n.New("en"), access.RoleViewer)
+
+	texts := stub.texts()
+	if len(texts) != 1 || strings.Contains(texts[0], "reboot") || !strings.Contains(texts[0], "uptime") {
+		t.Errorf("replies %q, want only uptime listed", texts)
+	}
+}
+
+func TestCommandRole(t *testing.T) {
+	tests := []struct {
+		roles []string
+		want  access.Role
+	}{
+		{nil, access.RoleOperator},
+		{[]string{config.RoleAdmin}, access.RoleAdmin},
+		{[]string{config.RoleAdmin, config.RoleViewer}, access.RoleViewer},
+		{[]string{"root", config.RoleOperator}, access.RoleOperator},
+	}
+
+	for _, tt := range tests {
+		if got := commandRole(config.Command{Roles: tt.roles}); got != tt.want {
+			t.Errorf("commandRole(%q) = %v, want %v", tt.roles, got, tt.want)
+		}
+	}
+}
+
+func TestLimitedBuffer(t *testing.T) {
+	b := &limitedBuffer{max: 5}
+	b.Write([]byte("abc"))
+	b.Write([]byte("defg"))
+	b.Write([]byte("h"))
+
+	if got := b.buf.String(); got != "abcde" || !b.truncated {
+		t.Errorf("buffer %q truncated=%v, want \"abcde\" truncated", got, b.truncated)
+	}
+}
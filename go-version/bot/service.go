@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"log"
+	"strconv"
+	"strings"
+	"system-monitor/i18n"
+	"system-monitor/monitor"
+	"time"
+)
+
+const (
+	// serviceCallbackPrefix marks /restart confirmation buttons
+	serviceCallbackPrefix = "svc:"
+	// maxServiceRef is the longest service name that fits into callback
+	// data after "svc:restart:<unix time>:"
+	maxServiceRef = 64 - len(serviceCallbackPrefix+"restart:0000000000:")
+)
+
+// handleServices shows the state of the watched services
+func (p *Poller) handleServices(chatID string, loc *i18n.Localizer) {
+	if len(p.cfg.Services) == 0 {
+		p.sendMessage(chatID, loc.T("bot.services_none"))
+		return
+	}
+
+	text := loc.T("bot.services_title", html.EscapeString(p.cfg.ComputerName)) + "\n\n"
+	for _, service := range monitor.GetServices(p.cfg.Services) {
+		icon, state := "✅", service.State
+		if !service.Running {
+			icon = "❌"
+		}
+		if service.Error != "" {
+			state = service.Error
+		}
+		text += loc.T("report.service_line", icon, html.EscapeString(service.Name), html.EscapeString(state)) + "\n"
+	}
+	text += "\n" + loc.T("bot.services_hint")
+
+	p.sendMessage(chatID, text)
+}
+
+// handleRestart asks for confirmation before restarting a watched service:
+// /restart <name>
+func (p *Poller) handleRestart(chatID string, loc *i18n.Localizer, args []string) {
+	if len(args) != 1 {
+		p.sendMessage(chatID, loc.T("bot.restart_usage"))
+		return
+	}
+
+	// Only watched services may be restarted
+	name, found := "", false
+	for _, service := range p.cfg.Services {
+		if strings.EqualFold(service, args[0]) {
+			name, found = service, true
+			break
+		}
+	}
+	if !found {
+		p.sendMessage(chatID, loc.T("bot.restart_unknown", html.EscapeString(args[0])))
+		return
+	}
+
+	// The button names the service rather than its position in the list,
+	// which may change with a config reload before the button is pressed
+	target := fmt.Sprintf("%d:%s", time.Now().Unix(), serviceRef(name))
+	keyboard := InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{{
+		{Text: loc.T("bot.restart_button"), CallbackData: serviceCallbackPrefix + "restart:" + target},
+		{Text: loc.T("bot.kill_cancel"), CallbackData: serviceCallbackPrefix + "cancel"},
+	}}}
+
+	p.sendKeyboard(chatID, loc.T("bot.restart_confirm", html.EscapeString(name), html.EscapeString(p.cfg.ComputerName)), keyboard)
+}
+
+// handleServiceCallback carries out a confirmed /restart; action is the
+// callback data without the prefix: "cancel" or "restart:<issued>:<service>"
+func (p *Poller) handleServiceCallback(chatID string, loc *i18n.Localizer, action string) {
+	if action == "cancel" {
+		p.sendMessage(chatID, loc.T("bot.restart_cancelled"))
+		return
+	}
+
+	parts := strings.SplitN(action, ":", 3)
+	if len(parts) != 3 || parts[0] != "restart" {
+		return
+	}
+
+	issued, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return
+	}
+
+	if time.Since(time.Unix(issued, 0)) > confirmTTL {
+		p.sendMessage(chatID, loc.T("bot.kill_expired"))
+		return
+	}
+
+	// The service may have been removed from the config since the button
+	// was sent
+	name, found := "", false
+	for _, service := range p.cfg.Services {
+		if serviceRef(service) == parts[2] {
+			name, found = service, true
+			break
+		}
+	}
+	if !found {
+		p.sendMessage(chatID, loc.T("bot.restart_unknown", html.EscapeString(parts[2])))
+		return
+	}
+
+	log.Printf("Перезапуск службы %s по запросу из чата %s", name, chatID)
+
+	if err := monitor.RestartService(name); err != nil {
+		log.Printf("Ошибка перезапуска службы %s: %v", name, err)
+		p.sendMessage(chatID, loc.T("bot.restart_error", html.EscapeString(name), html.EscapeString(err.Error())))
+		return
+	}
+
+	p.sendMessage(chatID, loc.T("bot.restarted", html.EscapeString(name)))
+}
+
+// serviceRef identifies a service in callback data. Telegram limits callback
+// data to 64 bytes, so a name too long for the button is replaced by a hash.
+func serviceRef(name string) string {
+	if len(name) <= maxServiceRef {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	return "#" + hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"testing"
+	"time"
+)
+
+// restartData finds the callback data of a restart button in a keyboard
+// recorded by the stub
+var restartData = regexp.MustCompile(`svc:restart:[^ \]]+`)
+
+func TestRestartButton(t *testing.T) {
+	long := strings.Repeat("very-long-service-name-", 4) + "worker"
+
+	tests := []struct {
+		name    string
+		service string
+	}{
+		{"short name", "nginx"},
+		{"long name", long},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, stub := newTestPoller(t, func(cfg *config.Config) {
+				cfg.Services = []string{"postgresql", tt.service}
+			})
+			loc := i18n.New("en")
+
+			// A private chat, whose rate limit lets the second reply
+			// through sooner
+			p.handleRestart("42", loc, []string{strings.ToUpper(tt.service)})
+
+			stub.mu.Lock()
+			markup := stub.calls[len(stub.calls)-1].fields["reply_markup"]
+			stub.mu.Unlock()
+			data := restartData.FindString(markup)
+			if data == "" {
+				t.Fatalf("no restart button in %q", markup)
+			}
+			if len(data) > 64 {
+				t.Errorf("callback data %q is %d bytes, Telegram allows 64", data, len(data))
+			}
+
+			// The service was removed by a config reload before the button
+			// was pressed, so nothing is restarted
+			p.cfg.Services = []string{"postgresql"}
+			p.handleServiceCallback("42", loc, strings.TrimPrefix(data, serviceCallbackPrefix))
+
+			texts := stub.texts()
+			if got := texts[len(texts)-1]; !strings.Contains(got, "is not watched") {
+				t.Errorf("reply %q, want the service reported as not watched", got)
+			}
+		})
+	}
+}
+
+func TestRestartRefusesUnwatched(t *testing.T) {
+	p, stub := newTestPoller(t, func(cfg *config.Config) {
+		cfg.Services = []string{"nginx"}
+	})
+
+	p.handleRestart("-100", i18n.New("en"), []string{"sshd"})
+
+	texts := stub.texts()
+	if len(texts) != 1 || !strings.Contains(texts[0], "Service sshd is not watched") {
+		t.Errorf("replies %q, want sshd refused", texts)
+	}
+}
+
+func TestServiceCallback(t *testing.T) {
+	stale := time.Now().Add(-confirmTTL - time.Minute).Unix()
+
+	tests := []struct {
+		name   string
+		action string
+		want   string
+	}{
+		{"cancel", "cancel", "Restart cancelled"},
+		{"expired", fmt.Sprintf("restart:%d:nginx", stale), "The confirmation has expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, stub := newTestPoller(t, func(cfg *config.Config) {
+				cfg.Services = []string{"nginx"}
+			})
+			p.handleServiceCallback("-100", i18n.New("en"), tt.action)
+
+			texts := stub.texts()
+			if len(texts) != 1 || !strings.Contains(texts[0], tt.want) {
+				t.Errorf("replies %q, want one containing %q", texts, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceRef(t *testing.T) {
+	if got := serviceRef("nginx"); got != "nginx" {
+		t.Errorf("serviceRef(nginx) = %q", got)
+	}
+
+	a := serviceRef(strings.Repeat("a", 100))
+	b := serviceRef(strings.Repeat("a", 99) + "b")
+	if len(a) > maxServiceRef || !strings.HasPrefix(a, "#") {
+		t.Errorf("serviceRef of a long name = %q, want a short hash", a)
+	}
+	if a == b {
+		t.Error("different long names have the same reference")
+	}
+}
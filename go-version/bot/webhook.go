@@ -0,0 +1,186 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	// secretTokenHeader carries the webhook secret set with setWebhook
+	secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+	// webhookQueueSize bounds updates accepted but not yet processed
+	webhookQueueSize = 100
+	// webhookShutdownTimeout limits how long in-flight requests may finish
+	webhookShutdownTimeout = 10 * time.Second
+)
+
+// StartWebhook registers the webhook with Telegram and serves updates until
+// ctx is done, then removes the webhook and stops the server
+func (p *Poller) StartWebhook(ctx context.Context) error {
+	hook := p.cfg.Webhook
+
+	endpoint, err := url.Parse(hook.URL)
+	if err != nil {
+		return fmt.Errorf("failed to parse webhook url: %w", err)
+	}
+	path := endpoint.Path
+	if path == "" {
+		path = "/"
+	}
+
+	// Updates are processed one at a time, in order, as with polling. On
+	// return the updates already accepted are processed before the worker
+	// stops; the channel is never closed, so a late handler cannot panic.
+	updates := make(chan Update, webhookQueueSize)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case update := <-updates:
+				p.processUpdate(ctx, update)
+			case <-done:
+				for {
+					select {
+					case update := <-updates:
+						p.processUpdate(ctx, update)
+					default:
+						return
+					}
+				}
+			}
+		}
+	}()
+	defer func() {
+		close(done)
+		<-stopped
+		// Commands are cancelled with ctx; wait for them to report back
+		p.commands.Wait()
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, p.webhookHandler(updates))
+	server := &http.Server{Addr: hook.Listen, Handler: mux}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if hook.CertFile != "" {
+			serveErr <- server.ListenAndServeTLS(hook.CertFile, hook.KeyFile)
+		} else {
+			serveErr <- server.ListenAndServe()
+		}
+	}()
+
+	log.Printf("Webhook слушает %s%s", hook.Listen, path)
+
+	p.RegisterComputer()
+	if err := p.setWebhook(ctx); err != nil {
+		server.Close()
+		return err
+	}
+	log.Printf("Webhook зарегистрирован: %s", hook.URL)
+
+	select {
+	case err := <-serveErr:
+		p.deleteWebhook()
+		return fmt.Errorf("webhook server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Println("Остановка webhook...")
+	if err := p.deleteWebhook(); err != nil {
+		log.Printf("Ошибка удаления webhook: %v", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to stop webhook server: %w", err)
+	}
+
+	return nil
+}
+
+// webhookHandler accepts updates posted by Telegram and queues them
+func (p *Poller) webhookHandler(updates chan<- Update) http.HandlerFunc {
+	secret := []byte(p.cfg.Webhook.SecretToken)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		given := []byte(r.Header.Get(secretTokenHeader))
+		if subtle.ConstantTimeCompare(given, secret) != 1 {
+			log.Printf("Webhook: отклонен запрос от %s с неверным секретом", r.RemoteAddr)
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		var update Update
+		if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+			http.Error(w, "invalid update", http.StatusBadRequest)
+			return
+		}
+
+		// Telegram retries updates that are not acknowledged with 2xx
+		select {
+		case updates <- update:
+			w.WriteHeader(http.StatusOK)
+		default:
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		}
+	}
+}
+
+// setWebhook points Telegram at the configured webhook URL. The certificate
+// is uploaded when one is configured, so that Telegram accepts a self-signed
+// one.
+func (p *Poller) setWebhook(ctx context.Context) error {
+	hook := p.cfg.Webhook
+	allowedUpdates := []string{"message", "callback_query"}
+
+	if hook.CertFile == "" {
+		return p.client.Call(ctx, "setWebhook", map[string]interface{}{
+			"url":                  hook.URL,
+			"secret_token":         hook.SecretToken,
+			"allowed_updates":      allowedUpdates,
+			"drop_pending_updates": hook.DropPendingUpdates,
+		}, nil)
+	}
+
+	cert, err := os.ReadFile(hook.CertFile)
+	if err != nil {
+		return fmt.Errorf("failed to read webhook certificate: %w", err)
+	}
+
+	// Multipart fields are strings; lists are passed as JSON
+	allowed, err := json.Marshal(allowedUpdates)
+	if err != nil {
+		return fmt.Errorf("failed to marshal allowed updates: %w", err)
+	}
+
+	fields := map[string]string{
+		"url":                  hook.URL,
+		"secret_token":         hook.SecretToken,
+		"allowed_updates":      string(allowed),
+		"drop_pending_updates": strconv.FormatBool(hook.DropPendingUpdates),
+	}
+	return p.client.CallWithFile(ctx, "setWebhook", fields, "certificate", filepath.Base(hook.CertFile), cert)
+}
+
+// deleteWebhook removes the webhook so that updates can be polled again
+func (p *Poller) deleteWebhook() error {
+	return p.client.Call(context.Background(), "deleteWebhook", map[string]interface{}{}, nil)
+}
@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"system-monitor/config"
+	"testing"
+)
+
+func TestWebhookHandler(t *testing.T) {
+	p, _ := newTestPoller(t, func(cfg *config.Config) {
+		cfg.Webhook = config.Webhook{URL: "https://example.com/hook", Listen: ":8443", SecretToken: "s3cret"}
+	})
+
+	tests := []struct {
+		name   string
+		method string
+		secret string
+		body   string
+		want   int
+	}{
+		{"accepted", http.MethodPost, "s3cret", `{"update_id": 1}`, http.StatusOK},
+		{"no secret", http.MethodPost, "", `{"update_id": 2}`, http.StatusForbidden},
+		{"wrong secret", http.MethodPost, "s3cre", `{"update_id": 3}`, http.StatusForbidden},
+		{"not a post", http.MethodGet, "s3cret", "", http.StatusMethodNotAllowed},
+		{"invalid update", http.MethodPost, "s3cret", "{", http.StatusBadRequest},
+		{"queue full", http.MethodPost, "s3cret", `{"update_id": 4}`, http.StatusServiceUnavailable},
+	}
+
+	updates := make(chan Update, 1)
+	handler := p.webhookHandler(updates)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/hook", strings.NewReader(tt.body))
+			if tt.secret != "" {
+				req.Header.Set(secretTokenHeader, tt.secret)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+
+	if len(updates) != 1 {
+		t.Fatalf("%d updates queued, want 1", len(updates))
+	}
+	if update := <-updates; update.UpdateID != 1 {
+		t.Errorf("queued update %d, want 1", update.UpdateID)
+	}
+}
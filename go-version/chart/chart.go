@@ -0,0 +1,204 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"time"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Point is a single value of a series
+type Point struct {
+	Time  time.Time
+	Value float64
+}
+
+// Series is a named line on a chart
+type Series struct {
+	Name   string
+	Color  color.RGBA
+	Points []Point
+}
+
+// Panel is one chart area with its own title and series. Values are
+// plotted on a fixed 0-100 scale since all metrics are percentages.
+type Panel struct {
+	Title  string
+	Series []Series
+}
+
+const (
+	width       = 900
+	panelHeight = 300
+	marginLeft  = 50
+	marginRight = 20
+	marginTop   = 30
+	marginBot   = 40
+	lineWidth   = 2
+)
+
+var (
+	background = color.RGBA{255, 255, 255, 255}
+	gridColor  = color.RGBA{225, 225, 225, 255}
+	axisColor  = color.RGBA{90, 90, 90, 255}
+	textColor  = color.RGBA{40, 40, 40, 255}
+)
+
+// Palette is the set of line colors assigned to series in order
+var Palette = []color.RGBA{
+	{220, 50, 47, 255},
+	{38, 139, 210, 255},
+	{133, 153, 0, 255},
+	{211, 54, 130, 255},
+	{181, 137, 0, 255},
+	{42, 161, 152, 255},
+	{108, 113, 196, 255},
+	{203, 75, 22, 255},
+}
+
+// Render draws the panels stacked vertically over [from, to] and returns a PNG image
+func Render(panels []Panel, from, to time.Time) ([]byte, error) {
+	if len(panels) == 0 {
+		return nil, fmt.Errorf("nothing to draw")
+	}
+	if !to.After(from) {
+		return nil, fmt.Errorf("invalid time range")
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, panelHeight*len(panels)))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	for i, panel := range panels {
+		area := image.Rect(marginLeft, i*panelHeight+marginTop, width-marginRight, (i+1)*panelHeight-marginBot)
+		drawPanel(img, area, panel, from, to)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// drawPanel draws the grid, axes, labels, legend and lines of one panel
+func drawPanel(img *image.RGBA, area image.Rectangle, panel Panel, from, to time.Time) {
+	drawText(img, area.Min.X, area.Min.Y-12, panel.Title, textColor)
+
+	// Horizontal grid with percent labels
+	for pct := 0; pct <= 100; pct += 25 {
+		y := area.Max.Y - pct*area.Dy()/100
+		hline(img, area.Min.X, area.Max.X, y, gridColor)
+		drawText(img, area.Min.X-40, y+4, fmt.Sprintf("%3d%%", pct), textColor)
+	}
+
+	// Vertical grid with time labels
+	layout := "15:04"
+	if to.Sub(from) > 48*time.Hour {
+		layout = "02.01"
+	}
+	const ticks = 6
+	for i := 0; i <= ticks; i++ {
+		x := area.Min.X + i*area.Dx()/ticks
+		vline(img, x, area.Min.Y, area.Max.Y, gridColor)
+		t := from.Add(time.Duration(int64(to.Sub(from)) * int64(i) / ticks))
+		drawText(img, x-len(layout)*7/2, area.Max.Y+16, t.Format(layout), textColor)
+	}
+
+	hline(img, area.Min.X, area.Max.X, area.Max.Y, axisColor)
+	vline(img, area.Min.X, area.Min.Y, area.Max.Y, axisColor)
+
+	// Legend under the time labels
+	legendX := area.Min.X
+	for _, series := range panel.Series {
+		fill(img, image.Rect(legendX, area.Max.Y+24, legendX+10, area.Max.Y+34), series.Color)
+		drawText(img, legendX+14, area.Max.Y+34, series.Name, textColor)
+		legendX += 14 + len(series.Name)*7 + 16
+	}
+
+	span := float64(to.Sub(from))
+	for _, series := range panel.Series {
+		var prev image.Point
+		for i, point := range series.Points {
+			value := min(max(point.Value, 0), 100)
+			p := image.Point{
+				X: area.Min.X + int(float64(point.Time.Sub(from))/span*float64(area.Dx())),
+				Y: area.Max.Y - int(value/100*float64(area.Dy())),
+			}
+			if i > 0 {
+				line(img, prev, p, series.Color)
+			}
+			prev = p
+		}
+	}
+}
+
+// line draws a thick line using Bresenham's algorithm
+func line(img *image.RGBA, a, b image.Point, c color.RGBA) {
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	e := dx + dy
+
+	for {
+		fill(img, image.Rect(a.X, a.Y, a.X+lineWidth, a.Y+lineWidth), c)
+		if a == b {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			a.X += sx
+		}
+		if e2 <= dx {
+			e += dx
+			a.Y += sy
+		}
+	}
+}
+
+func hline(img *image.RGBA, x1, x2, y int, c color.RGBA) {
+	fill(img, image.Rect(x1, y, x2+1, y+1), c)
+}
+
+func vline(img *image.RGBA, x, y1, y2 int, c color.RGBA) {
+	fill(img, image.Rect(x, y1, x+1, y2+1), c)
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r.Intersect(img.Bounds()), &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// drawText draws ASCII text with its baseline at y
+func drawText(img *image.RGBA, x, y int, text string, c color.RGBA) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{c},
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package chart
+
+import (
+	"fmt"
+	"system-monitor/storage"
+	"time"
+)
+
+// RenderHistory draws CPU/memory and per-disk usage from stored samples
+func RenderHistory(samples []storage.Sample, from, to time.Time) ([]byte, error) {
+	if len(samples) < 2 {
+		return nil, fmt.Errorf("not enough history to draw a chart")
+	}
+
+	cpu := Series{Name: "CPU", Color: Palette[0]}
+	memory := Series{Name: "RAM", Color: Palette[1]}
+	disks := make(map[string]*Series)
+	var order []string
+
+	for _, sample := range samples {
+		cpu.Points = append(cpu.Points, Point{Time: sample.Time, Value: sample.CPU})
+		memory.Points = append(memory.Points, Point{Time: sample.Time, Value: sample.Memory})
+
+		for _, disk := range sample.Disks {
+			series, exists := disks[disk.Mountpoint]
+			if !exists {
+				series = &Series{
+					Name:  disk.Mountpoint,
+					Color: Palette[len(order)%len(Palette)],
+				}
+				disks[disk.Mountpoint] = series
+				order = append(order, disk.Mountpoint)
+			}
+			series.Points = append(series.Points, Point{Time: sample.Time, Value: disk.Percent})
+		}
+	}
+
+	panels := []Panel{{Title: "CPU / RAM, %", Series: []Series{cpu, memory}}}
+
+	if len(order) > 0 {
+		panel := Panel{Title: "Disk usage, %"}
+		for _, mountpoint := range order {
+			panel.Series = append(panel.Series, *disks[mountpoint])
+		}
+		panels = append(panels, panel)
+	}
+
+	return Render(panels, from, to)
+}
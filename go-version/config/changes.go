@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// secretKeys are settings whose values are never shown in change lists
+var secretKeys = map[string]bool{
+	"telegram_token": true,
+}
+
+// restartKeys are settings read once at startup: the log file is opened
+// before the service starts, and the state files are kept next to it
+var restartKeys = map[string]bool{
+	"log_file": true,
+}
+
+// Changes lists the top-level settings that differ between two configs, by
+// their JSON keys. Plain values are shown as "key: old → new"; sections,
+// lists and secrets are named only.
+func Changes(prev, next *Config) []string {
+	var changes []string
+
+	prevValue := reflect.ValueOf(prev).Elem()
+	nextValue := reflect.ValueOf(next).Elem()
+	for i := 0; i < prevValue.NumField(); i++ {
+		field := prevValue.Type().Field(i)
+		key := fieldKey(field)
+
+		a, b := prevValue.Field(i), nextValue.Field(i)
+		if reflect.DeepEqual(a.Interface(), b.Interface()) {
+			continue
+		}
+
+		switch field.Type.Kind() {
+		case reflect.Slice:
+			// An empty list and a missing one mean the same
+			if a.Len() == 0 && b.Len() == 0 {
+				continue
+			}
+		case reflect.String:
+			if !secretKeys[key] {
+				changes = append(changes, fmt.Sprintf("%s: %q → %q", key, a.String(), b.String()))
+				continue
+			}
+		case reflect.Bool, reflect.Int:
+			changes = append(changes, fmt.Sprintf("%s: %v → %v", key, a.Interface(), b.Interface()))
+			continue
+		}
+
+		changes = append(changes, key)
+	}
+
+	return changes
+}
+
+// RestartRequired lists the settings that differ between two configs but
+// cannot be changed without restarting the service
+func RestartRequired(prev, next *Config) []string {
+	var keys []string
+
+	prevValue := reflect.ValueOf(prev).Elem()
+	nextValue := reflect.ValueOf(next).Elem()
+	for i := 0; i < prevValue.NumField(); i++ {
+		key := fieldKey(prevValue.Type().Field(i))
+		if restartKeys[key] && !reflect.DeepEqual(prevValue.Field(i).Interface(), nextValue.Field(i).Interface()) {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// fieldKey returns the JSON key of a config field
+func fieldKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if key == "" {
+		key = field.Name
+	}
+	return key
+}
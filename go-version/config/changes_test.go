@@ -0,0 +1,83 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		edit func(cfg *Config)
+		want []string
+	}{
+		{
+			name: "unchanged",
+			edit: func(cfg *Config) {},
+		},
+		{
+			name: "string",
+			edit: func(cfg *Config) { cfg.Language = "en" },
+			want: []string{`language: "ru" → "en"`},
+		},
+		{
+			name: "bool",
+			edit: func(cfg *Config) { cfg.EnablePolling = false },
+			want: []string{"enable_polling: true → false"},
+		},
+		{
+			name: "secret",
+			edit: func(cfg *Config) { cfg.TelegramToken = "new-token" },
+			want: []string{"telegram_token"},
+		},
+		{
+			name: "list",
+			edit: func(cfg *Config) { cfg.Services = append(cfg.Services, "nginx") },
+			want: []string{"services"},
+		},
+		{
+			name: "empty list",
+			edit: func(cfg *Config) { cfg.Tags = []string{} },
+		},
+		{
+			name: "section",
+			edit: func(cfg *Config) { cfg.Hub.Listen = ":9000" },
+			want: []string{"hub"},
+		},
+		{
+			name: "several",
+			edit: func(cfg *Config) {
+				cfg.ComputerName = "server"
+				cfg.Schedules = []Schedule{{Name: "daily", At: "08:00"}}
+			},
+			want: []string{`computer_name: "host" → "server"`, "schedules"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := &Config{ComputerName: "host", Language: "ru", TelegramToken: "token", EnablePolling: true}
+			next := *prev
+			tt.edit(&next)
+
+			if got := Changes(prev, &next); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Changes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	prev := &Config{LogFile: "monitor.log", ComputerName: "host"}
+
+	next := *prev
+	next.ComputerName = "server"
+	if got := RestartRequired(prev, &next); len(got) != 0 {
+		t.Errorf("RestartRequired() = %q for a reloadable change", got)
+	}
+
+	next.LogFile = "/var/log/monitor.log"
+	if got, want := RestartRequired(prev, &next), []string{"log_file"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RestartRequired() = %q, want %q", got, want)
+	}
+}
@@ -4,23 +4,513 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
 )
 
 // Config represents the application configuration
 type Config struct {
-	ComputerID     string `json:"computer_id"`
-	ComputerName   string `json:"computer_name"`
-	TelegramToken  string `json:"telegram_token"`
-	ChatID         string `json:"chat_id"`
-	ScheduleTime   string `json:"schedule_time"`
-	MonitorAllDisks bool  `json:"monitor_all_disks"`
-	Language       string `json:"language"`
-	LogFile        string `json:"log_file"`
-	EnablePolling  bool   `json:"enable_polling"`
+	ComputerID      string      `json:"computer_id"`
+	ComputerName    string      `json:"computer_name"`
+	Tags            []string    `json:"tags"`
+	Group           string      `json:"group"`
+	Owner           string      `json:"owner"`
+	TelegramToken   string      `json:"telegram_token"`
+	TelegramAPIURL  string      `json:"telegram_api_url"`
+	ChatID          string      `json:"chat_id"`
+	ScheduleTime    string      `json:"schedule_time"`
+	MonitorAllDisks bool        `json:"monitor_all_disks"`
+	Language        string      `json:"language"`
+	Timezone        string      `json:"timezone"`
+	Holidays        []string    `json:"holidays"`
+	LogFile         string      `json:"log_file"`
+	EnablePolling   bool        `json:"enable_polling"`
+	NotifyOnStop    bool        `json:"notify_on_stop"`
+	AlertInterval   string      `json:"alert_interval"`
+	Alerts          []AlertRule `json:"alerts"`
+	History         History     `json:"history"`
+	MetricsListen   string      `json:"metrics_listen"`
+	OfflineAfter    string      `json:"offline_after"`
+	Mode            string      `json:"mode"`
+	Hub             Hub         `json:"hub"`
+	Webhook         Webhook     `json:"webhook"`
+	Access          Access      `json:"access"`
+	Services        []string    `json:"services"`
+	Commands        []Command   `json:"commands"`
+	Channels        []Channel   `json:"channels"`
+	Routes          []Route     `json:"routes"`
+	Schedules       []Schedule  `json:"schedules"`
+}
+
+// Operating modes
+const (
+	// ModeStandalone monitors and answers for this computer only
+	ModeStandalone = "standalone"
+	// ModeHub owns Telegram polling and serves the agent API
+	ModeHub = "hub"
+	// ModeAgent reports to a hub instead of polling Telegram
+	ModeAgent = "agent"
+)
+
+// Hub configures the connection between a hub and its agents
+type Hub struct {
+	Listen            string `json:"listen"`
+	URL               string `json:"url"`
+	Token             string `json:"token"`
+	HeartbeatInterval string `json:"heartbeat_interval"`
+}
+
+// Bot roles, from least to most privileged
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// Access grants bot roles to Telegram user and chat IDs. When both lists
+// are empty only chat_id may use the bot, with the admin role.
+type Access struct {
+	Users    map[string]string `json:"users"`
+	Chats    map[string]string `json:"chats"`
+	AuditLog string            `json:"audit_log"`
+}
+
+// Command is a named command the bot may run with /run. Roles lists who
+// may run it; higher roles are always allowed, and without roles the
+// operator role is required.
+type Command struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Argv        []string `json:"argv"`
+	Timeout     string   `json:"timeout,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+}
+
+// Webhook configures receiving Telegram updates through an HTTP endpoint
+// instead of long polling
+type Webhook struct {
+	URL                string `json:"url"`
+	Listen             string `json:"listen"`
+	SecretToken        string `json:"secret_token"`
+	CertFile           string `json:"cert_file"`
+	KeyFile            string `json:"key_file"`
+	DropPendingUpdates bool   `json:"drop_pending_updates"`
+}
+
+// Enabled reports whether updates are received by webhook
+func (w Webhook) Enabled() bool {
+	return w.URL != ""
+}
+
+// Report profiles, selecting which sections a report contains
+const (
+	ProfileFull = "full"
+	// ProfileBrief has CPU, memory, disks and services
+	ProfileBrief = "brief"
+	// ProfileDisks has disks and disk forecasts
+	ProfileDisks = "disks"
+	// ProfileProcesses has the top processes by CPU and memory
+	ProfileProcesses = "processes"
+)
+
+// Schedule sends a report on a schedule. Exactly one of At (daily times
+// such as "08:00" or "08:00;20:00"), Cron (a five-field cron expression)
+// and Every (an interval such as "1h") is set. Without chats the report is
+// routed like any other; the profile defaults to full.
+type Schedule struct {
+	Name     string   `json:"name"`
+	At       string   `json:"at,omitempty"`
+	Cron     string   `json:"cron,omitempty"`
+	Every    string   `json:"every,omitempty"`
+	Timezone string   `json:"timezone,omitempty"`
+	Chats    []string `json:"chats,omitempty"`
+	Profile  string   `json:"profile,omitempty"`
+	// SkipWeekdays lists days without the report, e.g. ["sat", "sun"]
+	SkipWeekdays []string `json:"skip_weekdays,omitempty"`
+	// SkipHolidays skips the report on the dates listed in holidays
+	SkipHolidays bool `json:"skip_holidays,omitempty"`
+}
+
+// weekdays maps weekday names accepted in schedules to weekdays
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Holiday date layouts: a single date, or a date repeating every year
+const (
+	holidayLayout       = "2006-01-02"
+	yearlyHolidayLayout = "01-02"
+)
+
+// Excluded reports whether a report due at t, in the schedule's time
+// zone, is skipped because of its weekday or a holiday
+func (s Schedule) Excluded(t time.Time, holidays []string) bool {
+	for _, name := range s.SkipWeekdays {
+		if day, ok := weekdays[strings.ToLower(name)]; ok && day == t.Weekday() {
+			return true
+		}
+	}
+
+	if !s.SkipHolidays {
+		return false
+	}
+
+	date, yearly := t.Format(holidayLayout), t.Format(yearlyHolidayLayout)
+	for _, holiday := range holidays {
+		if holiday == date || holiday == yearly {
+			return true
+		}
+	}
+	return false
+}
+
+// ProfileName returns the report profile, full when not set
+func (s Schedule) ProfileName() string {
+	if s.Profile == "" {
+		return ProfileFull
+	}
+	return s.Profile
+}
+
+// Location returns the time zone of the schedule, local time when not set
+func (s Schedule) Location() *time.Location {
+	return loadLocation(s.Timezone)
+}
+
+// loadLocation returns the named time zone, local time when the name is
+// empty or unknown
+func loadLocation(name string) *time.Location {
+	if name == "" {
+		return time.Local
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// Interval returns the interval of an Every schedule
+func (s Schedule) Interval() time.Duration {
+	return durationOr(s.Every, 0)
+}
+
+// Notification channel types
+const (
+	ChannelTelegram = "telegram"
+	ChannelEmail    = "email"
+	ChannelWebhook  = "webhook"
+	ChannelSlack    = "slack"
+	ChannelMatrix   = "matrix"
+	ChannelFile     = "file"
+	ChannelSyslog   = "syslog"
+)
+
+// DefaultChannel is the built-in channel sending to chat_id
+const DefaultChannel = "telegram"
+
+// Kinds of notifications, used for routing
+const (
+	KindReport = "report"
+	KindAlert  = "alert"
+	// KindStatus covers agents going offline or coming back
+	KindStatus = "status"
+)
+
+// Alert severities
+const (
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Channel is a notification channel in addition to the built-in Telegram
+// chat. Which fields are used depends on the type.
+type Channel struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// ChatID is the chat of a telegram channel
+	ChatID string `json:"chat_id,omitempty"`
+	// URL is the endpoint of a webhook or slack channel
+	URL string `json:"url,omitempty"`
+	// Path is the file a file channel appends to
+	Path string `json:"path,omitempty"`
+	// Tag is the syslog tag, "system-monitor" by default
+	Tag    string `json:"tag,omitempty"`
+	Email  Email  `json:"email"`
+	Matrix Matrix `json:"matrix"`
+}
+
+// Email configures sending notifications over SMTP. With TLS the
+// connection is encrypted from the start (usually port 465); otherwise
+// STARTTLS is used when the server offers it.
+type Email struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+	TLS      bool     `json:"tls"`
+}
+
+// Matrix configures posting notifications to a Matrix room
+type Matrix struct {
+	Homeserver  string `json:"homeserver"`
+	AccessToken string `json:"access_token"`
+	RoomID      string `json:"room_id"`
+}
+
+// Route sends notifications of a kind and severity to channels. Empty
+// kind or severity match any. A notification goes to the channels of all
+// matching routes, or to the built-in telegram channel when none match.
+type Route struct {
+	Kind     string   `json:"kind,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+	Channels []string `json:"channels"`
+}
+
+// Matches reports whether the route applies to a notification
+func (r Route) Matches(kind, severity string) bool {
+	return (r.Kind == "" || r.Kind == kind) && (r.Severity == "" || r.Severity == severity)
+}
+
+// History configures the local metric history store
+type History struct {
+	Dir                string `json:"dir"`
+	Interval           string `json:"interval"`
+	Retention          string `json:"retention"`
+	DownsampleAfter    string `json:"downsample_after"`
+	DownsampleInterval string `json:"downsample_interval"`
+	ForecastWindow     string `json:"forecast_window"`
+}
+
+// AlertRule describes a threshold rule checked by the alert engine
+type AlertRule struct {
+	Name             string   `json:"name"`
+	Metric           string   `json:"metric"`
+	Mountpoint       string   `json:"mountpoint,omitempty"`
+	Service          string   `json:"service,omitempty"`
+	Operator         string   `json:"operator"`
+	Threshold        float64  `json:"threshold"`
+	ResolveThreshold *float64 `json:"resolve_threshold,omitempty"`
+	Duration         string   `json:"duration"`
+	RepeatInterval   string   `json:"repeat_interval,omitempty"`
+	Cooldown         string   `json:"cooldown,omitempty"`
+	// Severity is warning (the default) or critical
+	Severity string `json:"severity,omitempty"`
+}
+
+// SeverityLevel returns the rule severity, warning when not set
+func (r AlertRule) SeverityLevel() string {
+	if r.Severity == "" {
+		return SeverityWarning
+	}
+	return r.Severity
+}
+
+// Supported alert metrics
+const (
+	MetricCPU    = "cpu"
+	MetricMemory = "memory"
+	MetricDisk   = "disk"
+	// MetricDiskForecast is the estimated number of days until a disk is full
+	MetricDiskForecast = "disk_forecast"
+	// MetricService is 1 while a watched service is running and 0 otherwise
+	MetricService = "service"
+)
+
+// serviceDownRule is added for watched services when no service rule is configured
+var serviceDownRule = AlertRule{
+	Name:      "service_down",
+	Metric:    MetricService,
+	Operator:  "<",
+	Threshold: 1,
+	Duration:  "2m",
+}
+
+// ParseDuration parses a duration like time.ParseDuration and additionally
+// accepts a whole number of days, e.g. "7d". Negative durations are rejected.
+func ParseDuration(value string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(value); err != nil {
+			return 0, err
+		}
+	}
+
+	if d < 0 {
+		return 0, fmt.Errorf("negative duration %q", value)
+	}
+	return d, nil
+}
+
+// durationOr parses a duration, returning fallback when it is empty or invalid
+func durationOr(value string, fallback time.Duration) time.Duration {
+	d, err := ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// For returns how long the rule condition must hold before it fires
+func (r AlertRule) For() time.Duration {
+	d, _ := ParseDuration(r.Duration)
+	return d
+}
+
+// RepeatDuration returns how often a firing rule is re-notified (0 disables)
+func (r AlertRule) RepeatDuration() time.Duration {
+	d, _ := ParseDuration(r.RepeatInterval)
+	return d
+}
+
+// CooldownDuration returns the quiet period after a resolve during which
+// a new firing is not announced
+func (r AlertRule) CooldownDuration() time.Duration {
+	d, _ := ParseDuration(r.Cooldown)
+	return d
+}
+
+// ResolveLevel returns the threshold the value must cross back over to resolve
+func (r AlertRule) ResolveLevel() float64 {
+	if r.ResolveThreshold != nil {
+		return *r.ResolveThreshold
+	}
+	return r.Threshold
+}
+
+// DataPath returns the path of a state file stored next to the log file
+func (c *Config) DataPath(name string) string {
+	return filepath.Join(filepath.Dir(c.LogFile), name)
+}
+
+// ReportSchedules returns the configured report schedules, or a daily full
+// report at schedule_time when none are configured. Schedules without a
+// time zone get the configured one.
+func (c *Config) ReportSchedules() []Schedule {
+	if len(c.Schedules) == 0 {
+		return []Schedule{{Name: "daily", At: c.ScheduleTime, Timezone: c.Timezone}}
+	}
+
+	schedules := make([]Schedule, len(c.Schedules))
+	for i, schedule := range c.Schedules {
+		if schedule.Timezone == "" {
+			schedule.Timezone = c.Timezone
+		}
+		schedules[i] = schedule
+	}
+	return schedules
+}
+
+// Location returns the configured time zone, local time when not set
+func (c *Config) Location() *time.Location {
+	return loadLocation(c.Timezone)
+}
+
+// AlertRules returns the configured alert rules. When services are watched
+// but no rule covers them, a rule firing after a service has been down for
+// two minutes is added.
+func (c *Config) AlertRules() []AlertRule {
+	if len(c.Services) == 0 {
+		return c.Alerts
+	}
+
+	for _, rule := range c.Alerts {
+		if rule.Metric == MetricService {
+			return c.Alerts
+		}
+	}
+
+	return append(append([]AlertRule(nil), c.Alerts...), serviceDownRule)
+}
+
+// Interval returns the alert sampling interval
+func (c *Config) Interval() time.Duration {
+	return durationOr(c.AlertInterval, time.Minute)
+}
+
+// OfflineThreshold returns how long a computer may stay silent before it
+// is reported offline
+func (c *Config) OfflineThreshold() time.Duration {
+	return durationOr(c.OfflineAfter, 10*time.Minute)
+}
+
+// TimeoutDuration returns how long a command may run before it is killed
+func (c Command) TimeoutDuration() time.Duration {
+	return durationOr(c.Timeout, 30*time.Second)
+}
+
+// Heartbeat returns how often an agent reports to the hub
+func (h Hub) Heartbeat() time.Duration {
+	return durationOr(h.HeartbeatInterval, time.Minute)
+}
+
+// SampleInterval returns how often metrics are recorded to history
+func (h History) SampleInterval() time.Duration {
+	return durationOr(h.Interval, 5*time.Minute)
+}
+
+// RetentionPeriod returns how long history is kept
+func (h History) RetentionPeriod() time.Duration {
+	return durationOr(h.Retention, 30*24*time.Hour)
+}
+
+// DownsampleAge returns the age after which samples are downsampled
+func (h History) DownsampleAge() time.Duration {
+	return durationOr(h.DownsampleAfter, 48*time.Hour)
+}
+
+// ForecastPeriod returns how much history the disk-full forecast is based on
+func (h History) ForecastPeriod() time.Duration {
+	return durationOr(h.ForecastWindow, 7*24*time.Hour)
+}
+
+// DownsampleStep returns the bucket size used for downsampled samples
+func (h History) DownsampleStep() time.Duration {
+	return durationOr(h.DownsampleInterval, time.Hour)
 }
 
 // LoadConfig loads configuration from a JSON file
 func LoadConfig(path string) (*Config, error) {
+	cfg, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate required fields
+	if cfg.TelegramToken == "" || cfg.TelegramToken == "YOUR_BOT_TOKEN_HERE" {
+		return nil, fmt.Errorf("telegram_token is required in config.json")
+	}
+
+	if cfg.ChatID == "" || cfg.ChatID == "YOUR_CHAT_ID_HERE" {
+		return nil, fmt.Errorf("chat_id is required in config.json")
+	}
+
+	return cfg, nil
+}
+
+// Load loads configuration from a JSON file without requiring Telegram
+// credentials, for modes that only collect data locally
+func Load(path string) (*Config, error) {
 	file, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
@@ -31,37 +521,476 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
-	// Validate required fields
-	if cfg.TelegramToken == "" || cfg.TelegramToken == "YOUR_BOT_TOKEN_HERE" {
-		return nil, fmt.Errorf("telegram_token is required in config.json")
+	for i, rule := range cfg.Alerts {
+		if err := validateAlertRule(rule); err != nil {
+			return nil, fmt.Errorf("alerts[%d]: %w", i, err)
+		}
 	}
 
-	if cfg.ChatID == "" || cfg.ChatID == "YOUR_CHAT_ID_HERE" {
-		return nil, fmt.Errorf("chat_id is required in config.json")
+	if err := cfg.History.validate(); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+
+	if err := cfg.validateMode(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Webhook.validate(); err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+
+	if err := cfg.Access.validate(); err != nil {
+		return nil, fmt.Errorf("access: %w", err)
+	}
+
+	names := make(map[string]bool)
+	for i, command := range cfg.Commands {
+		if err := command.validate(); err != nil {
+			return nil, fmt.Errorf("commands[%d]: %w", i, err)
+		}
+		if names[command.Name] {
+			return nil, fmt.Errorf("commands[%d]: duplicate name %q", i, command.Name)
+		}
+		names[command.Name] = true
+	}
+
+	if err := cfg.validateChannels(); err != nil {
+		return nil, err
+	}
+
+	if cfg.Timezone != "" {
+		if _, err := time.LoadLocation(cfg.Timezone); err != nil {
+			return nil, fmt.Errorf("unknown timezone %q", cfg.Timezone)
+		}
+	}
+
+	for i, holiday := range cfg.Holidays {
+		_, err1 := time.Parse(holidayLayout, holiday)
+		_, err2 := time.Parse(yearlyHolidayLayout, holiday)
+		if err1 != nil && err2 != nil {
+			return nil, fmt.Errorf("holidays[%d]: %q is not YYYY-MM-DD or MM-DD", i, holiday)
+		}
+	}
+
+	if cfg.ScheduleTime != "" {
+		if err := validateClock(cfg.ScheduleTime); err != nil {
+			return nil, fmt.Errorf("schedule_time: %w", err)
+		}
+	}
+
+	names = make(map[string]bool)
+	for i, schedule := range cfg.Schedules {
+		if err := schedule.validate(); err != nil {
+			if schedule.Name != "" {
+				return nil, fmt.Errorf("schedules[%d] %q: %w", i, schedule.Name, err)
+			}
+			return nil, fmt.Errorf("schedules[%d]: %w", i, err)
+		}
+		if names[schedule.Name] {
+			return nil, fmt.Errorf("schedules[%d]: duplicate name %q", i, schedule.Name)
+		}
+		names[schedule.Name] = true
+	}
+
+	cfg.setDefaults()
+	return &cfg, nil
+}
+
+// Default returns a configuration with all defaults applied
+func Default() *Config {
+	cfg := &Config{}
+	cfg.setDefaults()
+	return cfg
+}
+
+// setDefaults fills in optional fields that were left empty
+func (c *Config) setDefaults() {
+	if c.Mode == "" {
+		c.Mode = ModeStandalone
+	}
+
+	if c.TelegramAPIURL == "" {
+		c.TelegramAPIURL = "https://api.telegram.org"
+	}
+
+	if c.ScheduleTime == "" {
+		c.ScheduleTime = "08:00"
+	}
+
+	if c.Language == "" {
+		c.Language = "ru"
+	}
+
+	if c.LogFile == "" {
+		c.LogFile = "monitor.log"
 	}
 
-	// Set defaults
-	if cfg.ScheduleTime == "" {
-		cfg.ScheduleTime = "08:00"
+	if c.AlertInterval == "" {
+		c.AlertInterval = "1m"
 	}
 
-	if cfg.Language == "" {
-		cfg.Language = "ru"
+	if c.History.Dir == "" {
+		c.History.Dir = c.DataPath("history")
 	}
 
-	if cfg.LogFile == "" {
-		cfg.LogFile = "monitor.log"
+	if c.Access.AuditLog == "" {
+		c.Access.AuditLog = c.DataPath("access_denied.log")
 	}
 
-	if cfg.ComputerID == "" {
+	if c.ComputerID == "" {
 		// Generate from hostname if not specified
 		hostname, _ := os.Hostname()
-		cfg.ComputerID = hostname
+		c.ComputerID = hostname
 	}
 
-	if cfg.ComputerName == "" {
-		cfg.ComputerName = cfg.ComputerID
+	if c.ComputerName == "" {
+		c.ComputerName = c.ComputerID
 	}
+}
 
-	return &cfg, nil
+// validateMode checks that the settings required by the mode are present
+func (c *Config) validateMode() error {
+	switch c.Mode {
+	case "", ModeStandalone:
+	case ModeHub:
+		if c.Hub.Listen == "" || c.Hub.Token == "" {
+			return fmt.Errorf("hub.listen and hub.token are required in hub mode")
+		}
+	case ModeAgent:
+		if c.Hub.URL == "" || c.Hub.Token == "" {
+			return fmt.Errorf("hub.url and hub.token are required in agent mode")
+		}
+	default:
+		return fmt.Errorf("unknown mode %q", c.Mode)
+	}
+
+	if c.OfflineAfter != "" {
+		if _, err := ParseDuration(c.OfflineAfter); err != nil {
+			return fmt.Errorf("invalid offline_after %q: %w", c.OfflineAfter, err)
+		}
+	}
+
+	if c.Hub.HeartbeatInterval != "" {
+		if _, err := ParseDuration(c.Hub.HeartbeatInterval); err != nil {
+			return fmt.Errorf("invalid hub.heartbeat_interval %q: %w", c.Hub.HeartbeatInterval, err)
+		}
+	}
+
+	return nil
+}
+
+// clockLayouts are the times of day the scheduler accepts
+var clockLayouts = []string{"15:04", "15:04:05"}
+
+// validateClock checks times of day such as 08:00, 08:00:30 or several of
+// them separated by semicolons, as in "08:00;20:00"
+func validateClock(value string) error {
+	for _, clock := range strings.Split(value, ";") {
+		valid := false
+		for _, layout := range clockLayouts {
+			if _, err := time.Parse(layout, clock); err == nil {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("invalid time %q, expected HH:MM", clock)
+		}
+	}
+	return nil
+}
+
+// minScheduleInterval is the shortest interval of an Every schedule
+const minScheduleInterval = time.Minute
+
+// validate checks that a schedule has exactly one timing and known settings
+func (s Schedule) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	set := 0
+	for _, value := range []string{s.At, s.Cron, s.Every} {
+		if value != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of at, cron and every is required")
+	}
+
+	if s.Cron != "" {
+		if len(strings.Fields(s.Cron)) != 5 {
+			return fmt.Errorf("cron must have five fields: minute hour day month weekday")
+		}
+		// The scheduler parses cron expressions with the same parser
+		if _, err := cron.ParseStandard(s.Cron); err != nil {
+			return fmt.Errorf("invalid cron %q: %w", s.Cron, err)
+		}
+	}
+
+	if s.At != "" {
+		if err := validateClock(s.At); err != nil {
+			return fmt.Errorf("at: %w", err)
+		}
+	}
+
+	if s.Every != "" {
+		interval, err := ParseDuration(s.Every)
+		if err != nil {
+			return fmt.Errorf("invalid every %q: %w", s.Every, err)
+		}
+		if interval < minScheduleInterval {
+			return fmt.Errorf("every must be at least %v", minScheduleInterval)
+		}
+	}
+
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", s.Timezone)
+		}
+	}
+
+	switch s.Profile {
+	case "", ProfileFull, ProfileBrief, ProfileDisks, ProfileProcesses:
+	default:
+		return fmt.Errorf("unknown profile %q", s.Profile)
+	}
+
+	for _, name := range s.SkipWeekdays {
+		if _, ok := weekdays[strings.ToLower(name)]; !ok {
+			return fmt.Errorf("unknown weekday %q", name)
+		}
+	}
+
+	return nil
+}
+
+// validateChannels checks notification channels and that routes only use
+// known channels
+func (c *Config) validateChannels() error {
+	names := map[string]bool{DefaultChannel: true}
+	for i, channel := range c.Channels {
+		if err := channel.validate(); err != nil {
+			return fmt.Errorf("channels[%d]: %w", i, err)
+		}
+		if names[channel.Name] {
+			return fmt.Errorf("channels[%d]: duplicate name %q", i, channel.Name)
+		}
+		names[channel.Name] = true
+	}
+
+	for i, route := range c.Routes {
+		switch route.Kind {
+		case "", KindReport, KindAlert, KindStatus:
+		default:
+			return fmt.Errorf("routes[%d]: unknown kind %q", i, route.Kind)
+		}
+		switch route.Severity {
+		case "", SeverityWarning, SeverityCritical:
+		default:
+			return fmt.Errorf("routes[%d]: unknown severity %q", i, route.Severity)
+		}
+		if len(route.Channels) == 0 {
+			return fmt.Errorf("routes[%d]: channels are required", i)
+		}
+		for _, name := range route.Channels {
+			if !names[name] {
+				return fmt.Errorf("routes[%d]: unknown channel %q", i, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks that the fields required by the channel type are set
+func (ch Channel) validate() error {
+	if ch.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	switch ch.Type {
+	case ChannelTelegram:
+		if ch.ChatID == "" {
+			return fmt.Errorf("chat_id is required")
+		}
+	case ChannelWebhook, ChannelSlack:
+		if !strings.HasPrefix(ch.URL, "http://") && !strings.HasPrefix(ch.URL, "https://") {
+			return fmt.Errorf("url must start with http:// or https://")
+		}
+	case ChannelEmail:
+		if ch.Email.Host == "" || ch.Email.From == "" || len(ch.Email.To) == 0 {
+			return fmt.Errorf("email.host, email.from and email.to are required")
+		}
+	case ChannelMatrix:
+		if ch.Matrix.Homeserver == "" || ch.Matrix.AccessToken == "" || ch.Matrix.RoomID == "" {
+			return fmt.Errorf("matrix.homeserver, matrix.access_token and matrix.room_id are required")
+		}
+	case ChannelFile:
+		if ch.Path == "" {
+			return fmt.Errorf("path is required")
+		}
+	case ChannelSyslog:
+	default:
+		return fmt.Errorf("unknown type %q", ch.Type)
+	}
+
+	return nil
+}
+
+// validate checks that every entry is a numeric Telegram ID with a known role
+func (a Access) validate() error {
+	lists := map[string]map[string]string{"users": a.Users, "chats": a.Chats}
+	for field, entries := range lists {
+		for id, role := range entries {
+			if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+				return fmt.Errorf("%s: invalid id %q", field, id)
+			}
+			switch role {
+			case RoleViewer, RoleOperator, RoleAdmin:
+			default:
+				return fmt.Errorf("%s[%s]: unknown role %q", field, id, role)
+			}
+			// Everyone in a chat would share the role
+			if field == "chats" && role != RoleViewer {
+				return fmt.Errorf("chats[%s]: a chat can only be granted %q, grant %q to users", id, RoleViewer, role)
+			}
+		}
+	}
+	return nil
+}
+
+// validate checks that a command can be run
+func (c Command) validate() error {
+	if c.Name == "" || strings.ContainsAny(c.Name, " \t") {
+		return fmt.Errorf("name is required and may not contain spaces")
+	}
+
+	if len(c.Argv) == 0 || c.Argv[0] == "" {
+		return fmt.Errorf("argv is required")
+	}
+
+	if c.Timeout != "" {
+		if _, err := ParseDuration(c.Timeout); err != nil {
+			return fmt.Errorf("invalid timeout %q: %w", c.Timeout, err)
+		}
+	}
+
+	for _, role := range c.Roles {
+		switch role {
+		case RoleViewer, RoleOperator, RoleAdmin:
+		default:
+			return fmt.Errorf("unknown role %q", role)
+		}
+	}
+
+	return nil
+}
+
+// validate checks the webhook settings when a webhook URL is set
+func (w Webhook) validate() error {
+	if !w.Enabled() {
+		return nil
+	}
+
+	if !strings.HasPrefix(w.URL, "https://") {
+		return fmt.Errorf("url must start with https://")
+	}
+
+	if w.Listen == "" {
+		return fmt.Errorf("listen is required")
+	}
+
+	// Telegram accepts 1-256 characters A-Z, a-z, 0-9, _ and -
+	if len(w.SecretToken) == 0 || len(w.SecretToken) > 256 {
+		return fmt.Errorf("secret_token must be 1-256 characters long")
+	}
+	for _, r := range w.SecretToken {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return fmt.Errorf("secret_token may only contain A-Z, a-z, 0-9, _ and -")
+		}
+	}
+
+	if (w.CertFile == "") != (w.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+
+	return nil
+}
+
+// validateAlertRule checks that an alert rule is well-formed
+func validateAlertRule(rule AlertRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	switch rule.Metric {
+	case MetricCPU, MetricMemory, MetricDisk, MetricDiskForecast, MetricService:
+	default:
+		return fmt.Errorf("unknown metric %q", rule.Metric)
+	}
+
+	switch rule.Operator {
+	case ">", ">=", "<", "<=":
+	default:
+		return fmt.Errorf("unknown operator %q", rule.Operator)
+	}
+
+	switch rule.Severity {
+	case "", SeverityWarning, SeverityCritical:
+	default:
+		return fmt.Errorf("unknown severity %q", rule.Severity)
+	}
+
+	durations := map[string]string{
+		"duration":        rule.Duration,
+		"repeat_interval": rule.RepeatInterval,
+		"cooldown":        rule.Cooldown,
+	}
+	for field, value := range durations {
+		if value == "" {
+			continue
+		}
+		if _, err := ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", field, value, err)
+		}
+	}
+
+	// The resolve threshold must lie on the "normal" side of the threshold
+	if rule.ResolveThreshold != nil {
+		level := *rule.ResolveThreshold
+		switch rule.Operator {
+		case ">", ">=":
+			if level > rule.Threshold {
+				return fmt.Errorf("resolve_threshold must not exceed threshold")
+			}
+		case "<", "<=":
+			if level < rule.Threshold {
+				return fmt.Errorf("resolve_threshold must not be below threshold")
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks the history durations
+func (h History) validate() error {
+	durations := map[string]string{
+		"interval":            h.Interval,
+		"retention":           h.Retention,
+		"downsample_after":    h.DownsampleAfter,
+		"downsample_interval": h.DownsampleInterval,
+		"forecast_window":     h.ForecastWindow,
+	}
+	for field, value := range durations {
+		if value == "" {
+			continue
+		}
+		if _, err := ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid %s %q: %w", field, value, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,295 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule Schedule
+		// wantErr is a substring of the expected error, empty when valid
+		wantErr string
+	}{
+		{"at", Schedule{Name: "daily", At: "08:00"}, ""},
+		{"at with seconds", Schedule{Name: "daily", At: "08:00:30"}, ""},
+		{"several times", Schedule{Name: "daily", At: "08:00;20:00"}, ""},
+		{"cron", Schedule{Name: "weekly", Cron: "0 9 * * mon"}, ""},
+		{"every", Schedule{Name: "hourly", Every: "1h"}, ""},
+		{"profile and chats", Schedule{Name: "brief", At: "08:00", Profile: ProfileBrief, Chats: []string{"-100"}}, ""},
+		{"full", Schedule{Name: "full", At: "08:00", Timezone: "Asia/Almaty", Profile: ProfileBrief, SkipWeekdays: []string{"Sat", "sunday"}}, ""},
+		{"no name", Schedule{At: "08:00"}, "name is required"},
+		{"no time", Schedule{Name: "empty"}, "exactly one"},
+		{"two times", Schedule{Name: "both", At: "08:00", Every: "1h"}, "exactly one"},
+		{"bad at", Schedule{Name: "daily", At: "8am"}, `invalid time "8am"`},
+		{"bad hour", Schedule{Name: "daily", At: "25:00"}, `invalid time "25:00"`},
+		{"bad second time", Schedule{Name: "daily", At: "08:00;20:61"}, `invalid time "20:61"`},
+		{"cron fields", Schedule{Name: "weekly", Cron: "0 9 * *"}, "five fields"},
+		{"cron minute", Schedule{Name: "weekly", Cron: "61 * * * *"}, "invalid cron"},
+		{"cron weekday", Schedule{Name: "weekly", Cron: "0 9 * * funday"}, "invalid cron"},
+		{"bad every", Schedule{Name: "often", Every: "soon"}, "invalid every"},
+		{"short every", Schedule{Name: "often", Every: "30s"}, "at least"},
+		{"timezone", Schedule{Name: "daily", At: "08:00", Timezone: "Mars/Olympus"}, "unknown timezone"},
+		{"profile", Schedule{Name: "daily", At: "08:00", Profile: "huge"}, "unknown profile"},
+		{"weekday", Schedule{Name: "daily", At: "08:00", SkipWeekdays: []string{"caturday"}}, "unknown weekday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.schedule.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadSchedules(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			config: `{"schedule_time": "09:00", "schedules": [{"name": "morning", "at": "08:00"}, {"name": "weekly", "cron": "0 9 * * 1"}]}`,
+		},
+		{
+			name:    "schedule time",
+			config:  `{"schedule_time": "25:99"}`,
+			wantErr: `schedule_time: invalid time "25:99"`,
+		},
+		{
+			name:    "named schedule",
+			config:  `{"schedules": [{"name": "morning", "at": "08:00"}, {"name": "weekly", "cron": "61 * * * *"}]}`,
+			wantErr: `schedules[1] "weekly": invalid cron`,
+		},
+		{
+			name:    "unnamed schedule",
+			config:  `{"schedules": [{"at": "08:00"}]}`,
+			wantErr: "schedules[0]: name is required",
+		},
+		{
+			name:    "duplicate name",
+			config:  `{"schedules": [{"name": "morning", "at": "08:00"}, {"name": "morning", "at": "09:00"}]}`,
+			wantErr: `schedules[1]: duplicate name "morning"`,
+		},
+		{
+			name:    "holiday",
+			config:  `{"holidays": ["2024-01-01", "03-08", "Jan 1"]}`,
+			wantErr: "holidays[2]",
+		},
+		{
+			name:    "timezone",
+			config:  `{"timezone": "Mars/Olympus"}`,
+			wantErr: `unknown timezone "Mars/Olympus"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Load(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleExcluded(t *testing.T) {
+	almaty, err := time.LoadLocation("Asia/Almaty")
+	if err != nil {
+		t.Skip("time zone database not available")
+	}
+
+	holidays := []string{"2024-05-09", "03-08"}
+
+	// 2024-03-08 is a Friday, 2024-03-09 a Saturday
+	friday := time.Date(2024, 3, 8, 9, 0, 0, 0, time.UTC)
+	saturday := time.Date(2024, 3, 9, 9, 0, 0, 0, time.UTC)
+	monday := time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		at       time.Time
+		want     bool
+	}{
+		{"no exclusions", Schedule{}, saturday, false},
+		{"skipped weekday", Schedule{SkipWeekdays: []string{"sat", "sun"}}, saturday, true},
+		{"full weekday name", Schedule{SkipWeekdays: []string{"Saturday"}}, saturday, true},
+		{"other weekday", Schedule{SkipWeekdays: []string{"sat", "sun"}}, monday, false},
+		{"yearly holiday", Schedule{SkipHolidays: true}, friday, true},
+		{"yearly holiday next year", Schedule{SkipHolidays: true}, friday.AddDate(1, 0, 0), true},
+		{"dated holiday", Schedule{SkipHolidays: true}, time.Date(2024, 5, 9, 9, 0, 0, 0, time.UTC), true},
+		{"dated holiday other year", Schedule{SkipHolidays: true}, time.Date(2025, 5, 9, 9, 0, 0, 0, time.UTC), false},
+		{"holidays not skipped", Schedule{}, friday, false},
+		{"working day", Schedule{SkipHolidays: true}, monday, false},
+		// 22:00 UTC on Friday is already Saturday in Almaty
+		{"weekday in schedule zone", Schedule{SkipWeekdays: []string{"sat"}}, time.Date(2024, 3, 8, 22, 0, 0, 0, time.UTC).In(almaty), true},
+		{"holiday in schedule zone", Schedule{SkipHolidays: true}, time.Date(2024, 3, 7, 22, 0, 0, 0, time.UTC).In(almaty), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Excluded(tt.at, holidays); got != tt.want {
+				t.Errorf("Excluded(%v) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccessValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		access  Access
+		wantErr string
+	}{
+		{"empty", Access{}, ""},
+		{"roles", Access{Users: map[string]string{"42": RoleAdmin, "7": RoleOperator}, Chats: map[string]string{"-100": RoleViewer}}, ""},
+		{"user id", Access{Users: map[string]string{"@admin": RoleAdmin}}, `users: invalid id "@admin"`},
+		{"unknown role", Access{Users: map[string]string{"42": "root"}}, `unknown role "root"`},
+		{"chat operator", Access{Chats: map[string]string{"-100": RoleOperator}}, `chats[-100]: a chat can only be granted "viewer"`},
+		{"chat admin", Access{Chats: map[string]string{"-100": RoleAdmin}}, `grant "admin" to users`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.access.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"5m", 5 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"7d", 7 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"0", 0, false},
+		{"-3d", 0, true},
+		{"-5m", 0, true},
+		{"1.5d", 0, true},
+		{"d", 0, true},
+		{"", 0, true},
+		{"week", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDuration(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAlertRule(t *testing.T) {
+	resolveBelow, resolveAbove := 80.0, 95.0
+
+	tests := []struct {
+		name    string
+		rule    AlertRule
+		wantErr string
+	}{
+		{"valid", AlertRule{Name: "cpu", Metric: MetricCPU, Operator: ">", Threshold: 90, Duration: "5m", Cooldown: "1d"}, ""},
+		{"resolve threshold", AlertRule{Name: "cpu", Metric: MetricCPU, Operator: ">", Threshold: 90, ResolveThreshold: &resolveBelow}, ""},
+		{"no name", AlertRule{Metric: MetricCPU, Operator: ">"}, "name is required"},
+		{"metric", AlertRule{Name: "x", Metric: "load", Operator: ">"}, `unknown metric "load"`},
+		{"operator", AlertRule{Name: "x", Metric: MetricCPU, Operator: "=="}, `unknown operator "=="`},
+		{"severity", AlertRule{Name: "x", Metric: MetricCPU, Operator: ">", Severity: "fatal"}, `unknown severity "fatal"`},
+		{"duration", AlertRule{Name: "x", Metric: MetricCPU, Operator: ">", Duration: "soon"}, "invalid duration"},
+		{"negative cooldown", AlertRule{Name: "x", Metric: MetricCPU, Operator: ">", Cooldown: "-3d"}, "invalid cooldown"},
+		{"negative repeat", AlertRule{Name: "x", Metric: MetricCPU, Operator: ">", RepeatInterval: "-1h"}, "invalid repeat_interval"},
+		{"resolve above", AlertRule{Name: "x", Metric: MetricCPU, Operator: ">", Threshold: 90, ResolveThreshold: &resolveAbove}, "must not exceed"},
+		{"resolve below", AlertRule{Name: "x", Metric: MetricDiskForecast, Operator: "<", Threshold: 90, ResolveThreshold: &resolveBelow}, "must not be below"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAlertRule(tt.rule)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWebhookValidate(t *testing.T) {
+	valid := Webhook{URL: "https://example.com/hook", Listen: ":8443", SecretToken: "abc_DEF-123"}
+
+	tests := []struct {
+		name    string
+		edit    func(w *Webhook)
+		wantErr string
+	}{
+		{"valid", func(w *Webhook) {}, ""},
+		{"disabled", func(w *Webhook) { *w = Webhook{} }, ""},
+		{"plain http", func(w *Webhook) { w.URL = "http://example.com/hook" }, "https://"},
+		{"no listen", func(w *Webhook) { w.Listen = "" }, "listen is required"},
+		{"no secret", func(w *Webhook) { w.SecretToken = "" }, "1-256 characters"},
+		{"long secret", func(w *Webhook) { w.SecretToken = strings.Repeat("a", 257) }, "1-256 characters"},
+		{"secret characters", func(w *Webhook) { w.SecretToken = "abc def" }, "may only contain"},
+		{"cert without key", func(w *Webhook) { w.CertFile = "cert.pem" }, "set together"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := valid
+			tt.edit(&w)
+			err := w.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"system-monitor/config"
+	"system-monitor/monitor"
+	"time"
+)
+
+const (
+	namespace    = "system_monitor"
+	topProcesses = 5
+	// networkTTL limits how often the host addresses are looked up
+	networkTTL = 10 * time.Minute
+	// shutdownTimeout limits how long in-flight scrapes may finish
+	shutdownTimeout = 5 * time.Second
+)
+
+// Server exposes system metrics in Prometheus text format
+type Server struct {
+	cfg        *config.Config
+	network    *monitor.IPInfo
+	networkAt  time.Time
+	networkMux sync.Mutex
+	// cpu measures CPU load between scrapes
+	cpu *monitor.CPUSampler
+}
+
+// NewServer creates a new metrics server
+func NewServer(cfg *config.Config) *Server {
+	return &Server{cfg: cfg, cpu: monitor.NewCPUSampler()}
+}
+
+// Run serves metrics until ctx is done or the listener fails
+func (s *Server) Run(ctx context.Context) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	server := &http.Server{Addr: s.cfg.MetricsListen, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Prometheus exporter слушает %s/metrics", s.cfg.MetricsListen)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// handleMetrics collects current metrics and writes them in exposition format
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	out := &writer{
+		host: []label{
+			{"host", s.cfg.ComputerID},
+			{"host_name", s.cfg.ComputerName},
+		},
+	}
+
+	s.writeCPU(out)
+	s.writeMemory(out)
+	s.writeDisks(out)
+	s.writeNetwork(out)
+	s.writeProcesses(out)
+	out.writeErrors()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(out.buf.Bytes())
+}
+
+func (s *Server) writeCPU(out *writer) {
+	cpuInfo, err := s.cpu.Info()
+	if err != nil {
+		out.scrapeError("cpu")
+		return
+	}
+
+	out.family("cpu_count", "gauge", "Number of logical CPUs.")
+	out.sample("cpu_count", float64(cpuInfo.Count))
+
+	out.family("cpu_usage_percent", "gauge", "Total CPU utilization in percent.")
+	out.sample("cpu_usage_percent", cpuInfo.Percent)
+}
+
+func (s *Server) writeMemory(out *writer) {
+	memInfo, err := monitor.GetMemoryInfo()
+	if err != nil {
+		out.scrapeError("memory")
+		return
+	}
+
+	out.family("memory_total_bytes", "gauge", "Total physical memory in bytes.")
+	out.sample("memory_total_bytes", float64(memInfo.Total))
+
+	out.family("memory_used_bytes", "gauge", "Used physical memory in bytes.")
+	out.sample("memory_used_bytes", float64(memInfo.Used))
+
+	out.family("memory_available_bytes", "gauge", "Available physical memory in bytes.")
+	out.sample("memory_available_bytes", float64(memInfo.Available))
+
+	out.family("memory_usage_percent", "gauge", "Physical memory utilization in percent.")
+	out.sample("memory_usage_percent", memInfo.Percent)
+}
+
+func (s *Server) writeDisks(out *writer) {
+	disks, err := monitor.GetDiskInfo()
+	if err != nil {
+		out.scrapeError("disk")
+		return
+	}
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(*monitor.DiskInfo) float64
+	}{
+		{"disk_total_bytes", "Filesystem size in bytes.", func(d *monitor.DiskInfo) float64 { return float64(d.Total) }},
+		{"disk_used_bytes", "Used filesystem space in bytes.", func(d *monitor.DiskInfo) float64 { return float64(d.Used) }},
+		{"disk_free_bytes", "Free filesystem space in bytes.", func(d *monitor.DiskInfo) float64 { return float64(d.Free) }},
+		{"disk_usage_percent", "Filesystem utilization in percent.", func(d *monitor.DiskInfo) float64 { return d.Percent }},
+	}
+
+	for _, metric := range metrics {
+		out.family(metric.name, "gauge", metric.help)
+		for _, disk := range disks {
+			out.sample(metric.name, metric.value(disk),
+				label{"device", disk.Device},
+				label{"mountpoint", disk.Mountpoint},
+				label{"fstype", disk.FSType})
+		}
+	}
+}
+
+func (s *Server) writeNetwork(out *writer) {
+	// The external IP is left out: it would start a new series on every change
+	info := s.networkInfo()
+	out.family("network_info", "gauge", "Network addresses of the host, value is always 1.")
+	out.sample("network_info", 1,
+		label{"hostname", info.Hostname},
+		label{"local_ip", info.LocalIP})
+
+	interfaces, err := monitor.GetInterfaceCounters()
+	if err != nil {
+		out.scrapeError("network")
+		return
+	}
+
+	metrics := []struct {
+		name  string
+		help  string
+		value func(monitor.InterfaceCounters) uint64
+	}{
+		{"network_receive_bytes_total", "Bytes received by the interface.", func(c monitor.InterfaceCounters) uint64 { return c.BytesRecv }},
+		{"network_transmit_bytes_total", "Bytes sent by the interface.", func(c monitor.InterfaceCounters) uint64 { return c.BytesSent }},
+		{"network_receive_packets_total", "Packets received by the interface.", func(c monitor.InterfaceCounters) uint64 { return c.PacketsRecv }},
+		{"network_transmit_packets_total", "Packets sent by the interface.", func(c monitor.InterfaceCounters) uint64 { return c.PacketsSent }},
+		{"network_receive_errors_total", "Receive errors of the interface.", func(c monitor.InterfaceCounters) uint64 { return c.ErrorsIn }},
+		{"network_transmit_errors_total", "Transmit errors of the interface.", func(c monitor.InterfaceCounters) uint64 { return c.ErrorsOut }},
+		{"network_receive_drops_total", "Incoming packets dropped by the interface.", func(c monitor.InterfaceCounters) uint64 { return c.DropsIn }},
+		{"network_transmit_drops_total", "Outgoing packets dropped by the interface.", func(c monitor.InterfaceCounters) uint64 { return c.DropsOut }},
+	}
+
+	for _, metric := range metrics {
+		out.family(metric.name, "counter", metric.help)
+		for _, counters := range interfaces {
+			out.sample(metric.name, float64(metric.value(counters)), label{"interface", counters.Name})
+		}
+	}
+}
+
+// networkInfo returns cached network information, refreshing it when stale
+func (s *Server) networkInfo() *monitor.IPInfo {
+	s.networkMux.Lock()
+	defer s.networkMux.Unlock()
+
+	if s.network != nil && time.Since(s.networkAt) < networkTTL {
+		return s.network
+	}
+
+	s.network = monitor.GetLocalIPInfo()
+	s.networkAt = time.Now()
+	return s.network
+}
+
+func (s *Server) writeProcesses(out *writer) {
+	topCPU, err := monitor.GetTopProcessesByCPU(topProcesses)
+	if err == nil {
+		out.family("process_cpu_percent", "gauge", "CPU utilization of the top processes by CPU, summed by name.")
+		names, values := sumByName(topCPU, func(proc *monitor.ProcessInfo) float64 { return proc.CPUPercent })
+		for _, name := range names {
+			out.sample("process_cpu_percent", values[name], label{"name", name})
+		}
+	} else {
+		out.scrapeError("process_cpu")
+	}
+
+	topMem, err := monitor.GetTopProcessesByMemory(topProcesses)
+	if err == nil {
+		out.family("process_resident_memory_bytes", "gauge", "Resident memory of the top processes by memory, summed by name.")
+		names, values := sumByName(topMem, func(proc *monitor.ProcessInfo) float64 { return proc.MemoryMB * 1024 * 1024 })
+		for _, name := range names {
+			out.sample("process_resident_memory_bytes", values[name], label{"name", name})
+		}
+	} else {
+		out.scrapeError("process_memory")
+	}
+}
+
+// sumByName adds up the values of processes sharing a name, so each name is
+// one series; PIDs are not used as labels because every restarted process
+// would start a new series. Names are returned in the order they first appear.
+func sumByName(procs []*monitor.ProcessInfo, value func(*monitor.ProcessInfo) float64) ([]string, map[string]float64) {
+	var names []string
+	values := make(map[string]float64)
+	for _, proc := range procs {
+		if _, ok := values[proc.Name]; !ok {
+			names = append(names, proc.Name)
+		}
+		values[proc.Name] += value(proc)
+	}
+	return names, values
+}
+
+// label is a Prometheus label pair
+type label struct {
+	name  string
+	value string
+}
+
+// writer builds a response in Prometheus text exposition format
+type writer struct {
+	buf    bytes.Buffer
+	host   []label
+	errors []string
+}
+
+// family writes the HELP and TYPE lines of a metric family
+func (w *writer) family(name, kind, help string) {
+	fmt.Fprintf(&w.buf, "# HELP %s_%s %s\n", namespace, name, help)
+	fmt.Fprintf(&w.buf, "# TYPE %s_%s %s\n", namespace, name, kind)
+}
+
+// sample writes one sample with the host labels followed by extra labels
+func (w *writer) sample(name string, value float64, labels ...label) {
+	all := append(append([]label{}, w.host...), labels...)
+
+	parts := make([]string, 0, len(all))
+	for _, l := range all {
+		parts = append(parts, fmt.Sprintf("%s=\"%s\"", l.name, escape(l.value)))
+	}
+
+	fmt.Fprintf(&w.buf, "%s_%s{%s} %g\n", namespace, name, strings.Join(parts, ","), value)
+}
+
+// scrapeError records that a collector failed
+func (w *writer) scrapeError(collector string) {
+	w.errors = append(w.errors, collector)
+}
+
+// writeErrors writes the failed collectors as one metric family
+func (w *writer) writeErrors() {
+	if len(w.errors) == 0 {
+		return
+	}
+
+	w.family("scrape_error", "gauge", "1 if a collector failed during this scrape.")
+	for _, collector := range w.errors {
+		w.sample("scrape_error", 1, label{"collector", collector})
+	}
+}
+
+// escape escapes a label value for the text exposition format
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
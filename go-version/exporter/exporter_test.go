@@ -0,0 +1,34 @@
+package exporter
+
+import (
+	"reflect"
+	"system-monitor/monitor"
+	"testing"
+)
+
+func TestSumByName(t *testing.T) {
+	procs := []*monitor.ProcessInfo{
+		{PID: 10, Name: "chrome", CPUPercent: 30},
+		{PID: 11, Name: "postgres", CPUPercent: 20},
+		{PID: 12, Name: "chrome", CPUPercent: 5},
+	}
+
+	names, values := sumByName(procs, func(proc *monitor.ProcessInfo) float64 { return proc.CPUPercent })
+
+	if want := []string{"chrome", "postgres"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %q, want %q", names, want)
+	}
+	if want := map[string]float64{"chrome": 35, "postgres": 20}; !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v, want %v", values, want)
+	}
+}
+
+func TestSample(t *testing.T) {
+	out := &writer{host: []label{{"host", "pc-1"}}}
+	out.sample("process_cpu_percent", 12.5, label{"name", `say "hi"`})
+
+	want := `system_monitor_process_cpu_percent{host="pc-1",name="say \"hi\""} 12.5` + "\n"
+	if got := out.buf.String(); got != want {
+		t.Errorf("sample wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,178 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"system-monitor/config"
+	"system-monitor/monitor"
+	"system-monitor/report"
+	"system-monitor/storage"
+	"time"
+)
+
+// retryDelay is the pause after a failed request to the hub
+const retryDelay = 10 * time.Second
+
+// Agent connects this computer to a hub: it sends heartbeats and answers
+// report requests routed to it
+type Agent struct {
+	cfg     *config.Config
+	store   *storage.Store
+	version string
+	baseURL string
+	client  *http.Client
+}
+
+// NewAgent creates a hub client. The store may be nil when history is unavailable.
+func NewAgent(cfg *config.Config, store *storage.Store, version string) *Agent {
+	return &Agent{
+		cfg:     cfg,
+		store:   store,
+		version: version,
+		baseURL: strings.TrimRight(cfg.Hub.URL, "/"),
+		client: &http.Client{
+			Timeout: longPollTimeout + 15*time.Second,
+		},
+	}
+}
+
+// Run sends heartbeats and serves report requests until ctx is done. It
+// returns once no request to the hub is in flight any more.
+func (a *Agent) Run(ctx context.Context) {
+	log.Printf("Режим агента: хаб %s", a.baseURL)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.heartbeatLoop(ctx)
+	}()
+	defer wg.Wait()
+
+	for ctx.Err() == nil {
+		requests, err := a.pollRequests(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Ошибка получения запросов от хаба: %v", err)
+			select {
+			case <-time.After(retryDelay):
+			case <-ctx.Done():
+			}
+			continue
+		}
+
+		for _, req := range requests {
+			a.answer(ctx, req)
+		}
+	}
+}
+
+// heartbeatLoop reports to the hub every heartbeat interval until ctx is done
+func (a *Agent) heartbeatLoop(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.Hub.Heartbeat())
+	defer ticker.Stop()
+
+	for {
+		if err := a.SendHeartbeat(ctx); err != nil {
+			log.Printf("Ошибка отправки heartbeat: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// SendHeartbeat reports this computer as alive with its uptime and load
+func (a *Agent) SendHeartbeat(ctx context.Context) error {
+	return a.post(ctx, heartbeatPath, Heartbeat{
+		ComputerID:   a.cfg.ComputerID,
+		ComputerName: a.cfg.ComputerName,
+		Tags:         a.cfg.Tags,
+		Group:        a.cfg.Group,
+		Owner:        a.cfg.Owner,
+		Version:      a.version,
+		Summary:      monitor.GetSummary(),
+	})
+}
+
+// answer collects a report and pushes it to the hub
+func (a *Agent) answer(ctx context.Context, req ReportRequest) {
+	log.Printf("Запрос отчета от хаба (%s)", req.ID)
+
+	push := ReportPush{
+		ComputerID: a.cfg.ComputerID,
+		RequestID:  req.ID,
+		Report:     report.Collect(a.cfg, a.store),
+	}
+
+	if err := a.post(ctx, reportPath, push); err != nil {
+		log.Printf("Ошибка отправки отчета хабу: %v", err)
+	}
+}
+
+// pollRequests waits for report requests addressed to this computer
+func (a *Agent) pollRequests(ctx context.Context) ([]ReportRequest, error) {
+	endpoint := a.baseURL + requestsPath + "?computer_id=" + url.QueryEscape(a.cfg.ComputerID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.cfg.Hub.Token)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("hub returned status %d", resp.StatusCode)
+	}
+
+	var requests []ReportRequest
+	if err := json.NewDecoder(resp.Body).Decode(&requests); err != nil {
+		return nil, fmt.Errorf("failed to decode requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// post sends a JSON payload to a hub endpoint
+func (a *Agent) post(ctx context.Context, path string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+a.cfg.Hub.Token)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("hub returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package hub
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"system-monitor/config"
+	"testing"
+	"time"
+)
+
+// inflight counts the requests a transport has not finished yet
+type inflight struct {
+	n    atomic.Int32
+	next http.RoundTripper
+}
+
+func (t *inflight) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.n.Add(1)
+	defer t.n.Add(-1)
+	return t.next.RoundTrip(req)
+}
+
+func TestAgentRunStopsRequests(t *testing.T) {
+	// The hub holds every request open until the agent gives up on it; a
+	// disconnect is only noticed once the body has been read
+	heartbeat := make(chan struct{})
+	var once sync.Once
+	hub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if r.URL.Path == heartbeatPath {
+			once.Do(func() { close(heartbeat) })
+		}
+		<-r.Context().Done()
+	}))
+	defer hub.Close()
+
+	cfg := &config.Config{ComputerID: "pc-1", Hub: config.Hub{URL: hub.URL, Token: "secret"}}
+	agent := NewAgent(cfg, nil, "test")
+	transport := &inflight{next: http.DefaultTransport}
+	agent.client.Transport = transport
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		agent.Run(ctx)
+	}()
+
+	select {
+	case <-heartbeat:
+	case <-time.After(10 * time.Second):
+		t.Fatal("no heartbeat sent")
+	}
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after the context was canceled")
+	}
+	if n := transport.n.Load(); n != 0 {
+		t.Errorf("%d requests still in flight after Run returned", n)
+	}
+}
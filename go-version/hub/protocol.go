@@ -0,0 +1,47 @@
+package hub
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"system-monitor/monitor"
+	"system-monitor/report"
+)
+
+// API endpoints served by the hub
+const (
+	heartbeatPath = "/api/v1/heartbeat"
+	requestsPath  = "/api/v1/requests"
+	reportPath    = "/api/v1/report"
+)
+
+// Heartbeat is sent periodically by each agent
+type Heartbeat struct {
+	ComputerID   string           `json:"computer_id"`
+	ComputerName string           `json:"computer_name"`
+	Tags         []string         `json:"tags,omitempty"`
+	Group        string           `json:"group,omitempty"`
+	Owner        string           `json:"owner,omitempty"`
+	Version      string           `json:"version"`
+	Summary      *monitor.Summary `json:"summary,omitempty"`
+}
+
+// ReportRequest asks an agent for a fresh report on behalf of a chat
+type ReportRequest struct {
+	ID       string `json:"id"`
+	ChatID   string `json:"chat_id"`
+	Language string `json:"language"`
+}
+
+// ReportPush carries a report from an agent to the hub
+type ReportPush struct {
+	ComputerID string         `json:"computer_id"`
+	RequestID  string         `json:"request_id,omitempty"`
+	Report     *report.Report `json:"report"`
+}
+
+// authorized checks the bearer token of a request in constant time
+func authorized(r *http.Request, token string) bool {
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
@@ -0,0 +1,316 @@
+package hub
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"net"
+	"net/http"
+	"sync"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"system-monitor/notify"
+	"system-monitor/registry"
+	"system-monitor/report"
+	"system-monitor/telegram"
+	"time"
+)
+
+const (
+	// longPollTimeout is how long an agent request poll is held open
+	longPollTimeout = 25 * time.Second
+	// queueSize bounds the pending requests per agent
+	queueSize = 16
+	// requestTTL is how long a request waits for the agent's answer
+	requestTTL = 5 * time.Minute
+	// watchInterval is how often silent agents are checked for
+	watchInterval = 30 * time.Second
+	// shutdownTimeout limits how long in-flight requests may finish
+	shutdownTimeout = 5 * time.Second
+)
+
+// Server is the hub side of the agent API. It tracks agents in the
+// registry, queues report requests for them and forwards their reports
+// to Telegram.
+type Server struct {
+	cfg      *config.Config
+	registry *registry.Registry
+	client   *telegram.Client
+	notifier notify.Notifier
+	queues   map[string]chan ReportRequest
+	pending  map[string]pendingRequest
+	reports  map[string]*report.Report
+	mu       sync.Mutex
+}
+
+// pendingRequest is a request waiting for the agent's report
+type pendingRequest struct {
+	ReportRequest
+	created time.Time
+}
+
+// NewServer creates a hub server backed by the given registry. Requested
+// reports are sent with the client; notices about agents going offline or
+// coming back go through the notifier.
+func NewServer(cfg *config.Config, reg *registry.Registry, client *telegram.Client, notifier notify.Notifier) *Server {
+	s := &Server{
+		cfg:      cfg,
+		client:   client,
+		notifier: notifier,
+		registry: reg,
+		queues:   make(map[string]chan ReportRequest),
+		pending:  make(map[string]pendingRequest),
+		reports:  make(map[string]*report.Report),
+	}
+	reg.SetStatusHandler(s.notifyStatus)
+	return s
+}
+
+// Run starts offline detection and the HTTP listener, and blocks until ctx
+// is done or the listener fails. It returns once the requests in flight and
+// the final flush of the registry are done, so the next owner of the
+// registry file does not race with them.
+func (s *Server) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		s.registry.Watch(ctx, watchInterval)
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(heartbeatPath, s.auth(s.handleHeartbeat))
+	mux.HandleFunc(requestsPath, s.auth(s.handleRequests))
+	mux.HandleFunc(reportPath, s.auth(s.handleReport))
+
+	server := &http.Server{
+		Addr:    s.cfg.Hub.Listen,
+		Handler: mux,
+		// Request contexts end with ctx, so held agent polls return at once
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Hub API слушает %s", s.cfg.Hub.Listen)
+	err := server.ListenAndServe()
+
+	cancel()
+	<-stopped
+	<-watched
+
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// RequestReport asks a remote computer for a report to be delivered to
+// chatID. If the computer is offline the last known report is sent instead.
+func (s *Server) RequestReport(computerID, chatID string, loc *i18n.Localizer) error {
+	comp, exists := s.registry.Get(computerID)
+	if !exists {
+		return fmt.Errorf("unknown computer %q", computerID)
+	}
+
+	if !comp.Online(time.Now()) {
+		s.mu.Lock()
+		last := s.reports[computerID]
+		s.mu.Unlock()
+
+		if last == nil {
+			return s.client.SendMessage(context.Background(), chatID, loc.T("hub.offline", html.EscapeString(comp.Name)))
+		}
+		return s.deliver(chatID, loc, loc.T("hub.stale_report", html.EscapeString(comp.Name), loc.DateTime(last.Time)), last)
+	}
+
+	req := ReportRequest{
+		ID:       newRequestID(),
+		ChatID:   chatID,
+		Language: loc.Lang(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Forget requests whose agent never answered
+	now := time.Now()
+	for id, p := range s.pending {
+		if now.Sub(p.created) > requestTTL {
+			delete(s.pending, id)
+		}
+	}
+
+	select {
+	case s.queue(computerID) <- req:
+		s.pending[req.ID] = pendingRequest{ReportRequest: req, created: now}
+		return nil
+	default:
+		return fmt.Errorf("too many pending requests for %q", computerID)
+	}
+}
+
+// queue returns the request queue of a computer; s.mu must be held
+func (s *Server) queue(computerID string) chan ReportRequest {
+	q, exists := s.queues[computerID]
+	if !exists {
+		q = make(chan ReportRequest, queueSize)
+		s.queues[computerID] = q
+	}
+	return q
+}
+
+// auth wraps a handler with bearer token verification
+func (s *Server) auth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r, s.cfg.Hub.Token) {
+			log.Printf("Hub: отклонен запрос без авторизации от %s", r.RemoteAddr)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleHeartbeat registers an agent and updates its last-seen time
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var hb Heartbeat
+	if err := json.NewDecoder(r.Body).Decode(&hb); err != nil || hb.ComputerID == "" {
+		http.Error(w, "invalid heartbeat", http.StatusBadRequest)
+		return
+	}
+
+	if _, known := s.registry.Get(hb.ComputerID); !known {
+		log.Printf("Hub: подключен агент %s (%s)", hb.ComputerName, hb.ComputerID)
+	}
+	s.registry.Touch(registry.Info{
+		ID:      hb.ComputerID,
+		Name:    hb.ComputerName,
+		Tags:    hb.Tags,
+		Group:   hb.Group,
+		Owner:   hb.Owner,
+		Summary: hb.Summary,
+	})
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleRequests long-polls for report requests addressed to an agent
+func (s *Server) handleRequests(w http.ResponseWriter, r *http.Request) {
+	computerID := r.URL.Query().Get("computer_id")
+	if computerID == "" {
+		http.Error(w, "computer_id is required", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	q := s.queue(computerID)
+	s.mu.Unlock()
+
+	var requests []ReportRequest
+	select {
+	case req := <-q:
+		requests = append(requests, req)
+	case <-time.After(longPollTimeout):
+	case <-r.Context().Done():
+		return
+	}
+
+	// Drain whatever else is already waiting
+drain:
+	for len(requests) < queueSize {
+		select {
+		case req := <-q:
+			requests = append(requests, req)
+		default:
+			break drain
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(requests)
+}
+
+// handleReport stores a pushed report and forwards it to the requesting chat
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var push ReportPush
+	if err := json.NewDecoder(r.Body).Decode(&push); err != nil || push.Report == nil || push.ComputerID == "" {
+		http.Error(w, "invalid report", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.reports[push.ComputerID] = push.Report
+	req, requested := s.pending[push.RequestID]
+	delete(s.pending, push.RequestID)
+	s.mu.Unlock()
+
+	if requested {
+		if err := s.deliver(req.ChatID, i18n.New(req.Language), "", push.Report); err != nil {
+			log.Printf("Hub: ошибка отправки отчета %s: %v", push.ComputerID, err)
+			http.Error(w, "delivery failed", http.StatusBadGateway)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deliver renders a report and sends it to a chat with an optional header line
+func (s *Server) deliver(chatID string, loc *i18n.Localizer, header string, r *report.Report) error {
+	message, err := report.HTML(loc).Render(r)
+	if err != nil {
+		return err
+	}
+
+	if header != "" {
+		message = header + "\n\n" + message
+	}
+
+	return s.client.SendMessage(context.Background(), chatID, message)
+}
+
+// notifyStatus tells the chat that an agent stopped reporting or came back
+func (s *Server) notifyStatus(comp registry.Computer, online bool, silence time.Duration) {
+	loc := i18n.ForChat(s.cfg.ChatID, s.cfg.Language)
+
+	message := loc.T("hub.went_offline", html.EscapeString(comp.Name), loc.Duration(silence))
+	if online {
+		message = loc.T("hub.back_online", html.EscapeString(comp.Name), loc.Duration(silence))
+	}
+
+	if err := s.notifier.Notify(context.Background(), notify.Message{Kind: config.KindStatus, Text: message}); err != nil {
+		log.Printf("Hub: ошибка отправки уведомления о статусе %s: %v", comp.ID, err)
+	}
+}
+
+// newRequestID returns a random request identifier
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,131 @@
+package hub
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"system-monitor/registry"
+	"system-monitor/telegram"
+	"testing"
+)
+
+// botStub is a Bot API server that records the chats messages went to
+type botStub struct {
+	mu    sync.Mutex
+	chats []string
+}
+
+func (b *botStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ChatID string `json:"chat_id"`
+	}
+	json.NewDecoder(r.Body).Decode(&payload)
+
+	b.mu.Lock()
+	b.chats = append(b.chats, payload.ChatID)
+	b.mu.Unlock()
+
+	w.Write([]byte(`{"ok": true, "result": {"message_id": 1}}`))
+}
+
+// newTestHub starts a hub API backed by an in-memory registry and returns
+// the config an agent needs to reach it
+func newTestHub(t *testing.T) (*Server, *config.Config, *botStub) {
+	t.Helper()
+
+	bot := &botStub{}
+	api := httptest.NewServer(bot)
+	t.Cleanup(api.Close)
+
+	cfg := &config.Config{
+		ComputerID:     "pc-1",
+		ComputerName:   "office-pc",
+		TelegramToken:  "test",
+		TelegramAPIURL: api.URL,
+		Language:       "en",
+		Hub:            config.Hub{Token: "secret"},
+	}
+
+	reg, err := registry.Open("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(cfg, reg, telegram.NewClient(cfg), nil)
+
+	// The same routes Run serves
+	mux := http.NewServeMux()
+	mux.HandleFunc(heartbeatPath, s.auth(s.handleHeartbeat))
+	mux.HandleFunc(requestsPath, s.auth(s.handleRequests))
+	mux.HandleFunc(reportPath, s.auth(s.handleReport))
+	hub := httptest.NewServer(mux)
+	t.Cleanup(hub.Close)
+
+	cfg.Hub.URL = hub.URL
+	return s, cfg, bot
+}
+
+func TestReportRouting(t *testing.T) {
+	s, cfg, bot := newTestHub(t)
+	agent := NewAgent(cfg, nil, "test")
+	ctx := context.Background()
+	loc := i18n.New("en")
+
+	if err := s.RequestReport("pc-1", "42", loc); err == nil {
+		t.Error("a report was requested from a computer that never reported")
+	}
+
+	if err := agent.SendHeartbeat(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if comp, ok := s.registry.Get("pc-1"); !ok || comp.Name != "office-pc" {
+		t.Fatalf("registry has %+v after a heartbeat", comp)
+	}
+
+	if err := s.RequestReport("pc-1", "42", loc); err != nil {
+		t.Fatal(err)
+	}
+	requests, err := agent.pollRequests(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 || requests[0].ChatID != "42" {
+		t.Fatalf("agent got requests %+v, want one for chat 42", requests)
+	}
+
+	agent.answer(ctx, requests[0])
+
+	bot.mu.Lock()
+	defer bot.mu.Unlock()
+	if len(bot.chats) == 0 || bot.chats[0] != "42" {
+		t.Errorf("report sent to chats %q, want 42", bot.chats)
+	}
+}
+
+func TestAPIRequiresToken(t *testing.T) {
+	_, cfg, _ := newTestHub(t)
+
+	for _, token := range []string{"", "Bearer wrong", "secret"} {
+		req, err := http.NewRequest(http.MethodPost, cfg.Hub.URL+heartbeatPath, strings.NewReader(`{"computer_id": "pc-2"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("Authorization %q: status %d, want %d", token, resp.StatusCode, http.StatusUnauthorized)
+		}
+	}
+}
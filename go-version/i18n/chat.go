@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// Per-chat language overrides, persisted to a JSON file
+var (
+	chatLangs    = make(map[string]string)
+	chatLangsMux sync.RWMutex
+	chatLangPath string
+)
+
+// LoadChatLanguages loads per-chat language overrides from path; later
+// changes made with SetChatLanguage are saved to the same file
+func LoadChatLanguages(path string) error {
+	chatLangsMux.Lock()
+	defer chatLangsMux.Unlock()
+
+	chatLangPath = path
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read chat languages: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &chatLangs); err != nil {
+		return fmt.Errorf("failed to parse chat languages: %w", err)
+	}
+
+	return nil
+}
+
+// SetChatLanguage overrides the language of a chat and persists it
+func SetChatLanguage(chatID, lang string) error {
+	if !Supported(lang) {
+		return fmt.Errorf("unsupported language %q", lang)
+	}
+
+	chatLangsMux.Lock()
+	defer chatLangsMux.Unlock()
+
+	chatLangs[chatID] = lang
+
+	if chatLangPath == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(chatLangs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal chat languages: %w", err)
+	}
+
+	if err := os.WriteFile(chatLangPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write chat languages: %w", err)
+	}
+
+	return nil
+}
+
+// ForChat returns a localizer for a chat, using its override if one was
+// set and fallback otherwise
+func ForChat(chatID, fallback string) *Localizer {
+	chatLangsMux.RLock()
+	defer chatLangsMux.RUnlock()
+
+	if lang, ok := chatLangs[chatID]; ok {
+		return New(lang)
+	}
+	return New(fallback)
+}
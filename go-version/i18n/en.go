@@ -0,0 +1,167 @@
+package i18n
+
+var en = map[string]string{
+	"units.bytes":     "B,KB,MB,GB,TB,PB",
+	"units.days":      "d",
+	"units.hours":     "h",
+	"units.minutes":   "min",
+	"format.datetime": "2006-01-02 15:04:05",
+
+	"report.title":           "System status report",
+	"report.computer":        "Computer:",
+	"report.time":            "Time:",
+	"report.network":         "Network:",
+	"report.hostname":        "Hostname: %s",
+	"report.local_ip":        "Local IP: %s",
+	"report.external_ip":     "External IP: %s",
+	"report.cpu":             "CPU:",
+	"report.cores":           "Cores: %d",
+	"report.load":            "Load: %.1f%%",
+	"report.memory":          "Memory:",
+	"report.total":           "Total: %s",
+	"report.used":            "Used: %s (%.1f%%)",
+	"report.available":       "Available: %s",
+	"report.free":            "Free: %s",
+	"report.disks":           "Disks:",
+	"report.trend":           "Changes over 24 h:",
+	"report.trend_cpu":       "CPU: average %.1f%%, peak %.1f%%",
+	"report.trend_memory":    "Memory: average %.1f%%, peak %.1f%%",
+	"report.forecast":        "Disk full forecast:",
+	"report.forecast_line":   "%s: full in ~%.0f days (%s/day)",
+	"report.top_cpu":         "Top processes (CPU):",
+	"report.top_cpu_line":    "%s: %.1f%% (PID: %d)",
+	"report.top_memory":      "Top processes (memory):",
+	"report.top_memory_line": "%s: %.0f MB (%.1f%%)",
+	"report.services":        "Services:",
+	"report.service_line":    "%s %s: %s",
+	"report.errors":          "Data collection errors:",
+
+	"alert.firing":          "🚨 <b>Alert: %s</b>\n\n🖥️ <b>Computer:</b> %s\n%s: %s (threshold %s %s)",
+	"alert.repeat":          "🔁 <b>Alert still firing: %s</b>\n\n🖥️ <b>Computer:</b> %s\n%s: %s (threshold %s %s)\nDuration: %s",
+	"alert.resolved":        "✅ <b>Resolved: %s</b>\n\n🖥️ <b>Computer:</b> %s\n%s: %s\nDuration: %s",
+	"alert.metric.cpu":      "CPU load",
+	"alert.metric.memory":   "Memory",
+	"alert.metric.disk":     "Disk %s",
+	"alert.metric.forecast": "Disk %s full in",
+	"alert.value.days":      "%.1f days",
+	"alert.value.no_growth": "∞ (no growth detected)",
+	"alert.service_down":    "🚨 <b>Alert: %s</b>\n\n🖥️ <b>Computer:</b> %s\nService %s is stopped (%s)",
+	"alert.service_repeat":  "🔁 <b>Alert still firing: %s</b>\n\n🖥️ <b>Computer:</b> %s\nService %s is stopped (%s)\nDuration: %s",
+	"alert.service_up":      "✅ <b>Resolved: %s</b>\n\n🖥️ <b>Computer:</b> %s\nService %s is running again\nDuration: %s",
+
+	"bot.unknown_command":     "Unknown command. Use /help to list commands.",
+	"bot.no_computers":        "No computers available",
+	"bot.choose_computer":     "Choose a computer:",
+	"bot.no_registered":       "No computers registered",
+	"bot.status_title":        "📊 <b>Computer status:</b>",
+	"bot.online":              "Online",
+	"bot.offline":             "Offline",
+	"bot.last_seen":           "Last seen: %s (%s ago)",
+	"bot.report_error":        "Failed to create report: %s",
+	"bot.no_history":          "Metric history is not available",
+	"bot.bad_period":          "Invalid period. Examples: /chart 1h, /chart 24h, /chart 7d",
+	"bot.history_error":       "Failed to read history: %s",
+	"bot.not_enough_data":     "Not enough data for a chart over %s",
+	"bot.chart_caption":       "📈 <b>%s</b> — last %s",
+	"bot.lang_current":        "Current language: %s\nAvailable: %s\n\nUsage: /lang ru",
+	"bot.lang_unknown":        "Unknown language %s. Available: %s",
+	"bot.lang_set":            "Language changed: English",
+	"bot.lang_error":          "Failed to save language: %s",
+	"hub.offline":             "❌ %s is offline, no reports yet",
+	"hub.stale_report":        "⚠️ %s is offline. Last report from %s:",
+	"bot.request_sent":        "⏳ Request sent to %s, waiting for the report",
+	"bot.request_error":       "Failed to request report: %s",
+	"hub.went_offline":        "❌ <b>%s</b> stopped reporting (silent for %s)",
+	"hub.back_online":         "✅ <b>%s</b> is back online (was silent for %s)",
+	"outbox.delayed":          "⏳ <i>(delayed, generated at %s)</i>",
+	"app.stopping":            "⏹ <b>%s</b>: monitoring stopped",
+	"app.reloaded":            "🔄 <b>%s</b>: configuration reloaded\n%s",
+	"app.reload_failed":       "⚠️ <b>%s</b>: new configuration rejected, keeping the old one\n<code>%s</code>",
+	"bot.summary":             "CPU %.0f%% · RAM %.0f%% · Disk %.0f%% · up %s",
+	"bot.no_match":            "No computers match the filter",
+	"bot.computers_title":     "🖥 <b>Computers:</b>",
+	"bot.computer_group":      "Group: %s",
+	"bot.computer_tags":       "Tags: %s",
+	"bot.computer_owner":      "Owner: %s",
+	"bot.computer_seen":       "Last seen: %s",
+	"bot.unknown_computer":    "Computer %s not found. List: /computers",
+	"bot.rename_usage":        "Usage: /rename &lt;id&gt; &lt;new name&gt;",
+	"bot.renamed":             "✏️ Computer %s renamed to <b>%s</b>",
+	"bot.forget_usage":        "Usage: /forget &lt;id&gt;",
+	"bot.forget_local":        "The computer running the bot cannot be removed",
+	"bot.forget_error":        "Failed to remove computer: %s",
+	"bot.forgotten":           "🗑 %s removed from the list",
+	"bot.access_denied":       "⛔ Access denied. Your ID: <code>%d</code> - send it to the administrator",
+	"bot.access_insufficient": "⛔ Not enough rights for %s (role %s required)",
+	"bot.proc_error":          "Failed to list processes: %s",
+	"bot.top_empty":           "No processes found",
+	"bot.top_title":           "⚙️ <b>Processes on %s</b> (%d of %d):",
+	"bot.top_line":            "<code>%d</code> %s — CPU %.1f%% · RAM %.1f MB",
+	"bot.top_hint":            "Details: /proc &lt;pid&gt;, stop: /kill &lt;pid&gt;",
+	"bot.proc_usage":          "Usage: /proc &lt;pid&gt;",
+	"bot.proc_not_found":      "Process %d not found",
+	"bot.proc_title":          "⚙️ <b>%s</b> (PID %d)",
+	"bot.proc_usage_line":     "CPU %.1f%% · RAM %.1f MB (%.1f%%)",
+	"bot.proc_user":           "User: %s",
+	"bot.proc_started":        "Started: %s",
+	"bot.proc_threads":        "Threads: %d · parent: %d",
+	"bot.proc_status":         "Status: %s",
+	"bot.proc_exe":            "Executable: <code>%s</code>",
+	"bot.proc_cmdline":        "Command line: <code>%s</code>",
+	"bot.proc_files":          "Open files (%d):",
+	"bot.proc_files_more":     "…and %d more",
+	"bot.kill_usage":          "Usage: /kill &lt;pid&gt;",
+	"bot.kill_confirm":        "⚠️ <b>Stop this process?</b>",
+	"bot.kill_terminate":      "Terminate",
+	"bot.kill_force":          "Force kill",
+	"bot.kill_cancel":         "Cancel",
+	"bot.kill_cancelled":      "Process stop cancelled",
+	"bot.kill_expired":        "The confirmation has expired, send /kill again",
+	"bot.kill_error":          "Failed to stop process %d: %s",
+	"bot.terminated":          "✅ Process %d was asked to terminate",
+	"bot.killed":              "✅ Process %d was killed",
+	"bot.services_none":       "No services are watched. Add them to the services setting in config.json",
+	"bot.services_title":      "🧩 <b>Services on %s:</b>",
+	"bot.services_hint":       "Restart: /restart &lt;name&gt;",
+	"bot.restart_usage":       "Usage: /restart &lt;service name&gt;",
+	"bot.restart_unknown":     "Service %s is not watched. List: /services",
+	"bot.restart_button":      "Restart",
+	"bot.restart_confirm":     "⚠️ <b>Restart service %s on %s?</b>",
+	"bot.restart_cancelled":   "Restart cancelled",
+	"bot.restart_error":       "Failed to restart %s: %s",
+	"bot.restarted":           "✅ Service %s restarted",
+	"bot.run_title":           "▶️ <b>Available commands:</b>",
+	"bot.run_none":            "No commands are available to run",
+	"bot.run_unknown":         "Command %s is not configured. List: /run",
+	"bot.run_result":          "▶️ <b>%s</b>: exit code %d, %s",
+	"bot.run_timeout":         "⏱ <b>%s</b> was stopped after the timeout (%s)",
+	"bot.run_error":           "Failed to start %s: %s",
+	"bot.run_no_output":       "(no output)",
+	"bot.run_truncated":       "… output truncated to %d KB",
+	"bot.schedule_at":         "daily at %s",
+	"bot.schedule_every":      "every %s",
+	"bot.schedule_cron":       "on schedule <code>%s</code>",
+	"bot.help": `📖 <b>Available commands:</b>
+
+/info - Detailed information about a computer
+/status [tag:office] - Brief status of computers
+/computers [group:warehouse] - List computers with tags and groups
+/rename &lt;id&gt; &lt;name&gt; - Rename a computer
+/forget &lt;id&gt; - Remove a computer from the list
+/chart [1h|24h|7d] - CPU, memory and disk charts
+/top [cpu|mem|name] [N] [name] - Processes of this computer
+/proc &lt;pid&gt; - Process details
+/kill &lt;pid&gt; - Stop a process (with confirmation)
+/services - State of watched services
+/restart &lt;name&gt; - Restart a service (with confirmation)
+/run [name] - Run an allowed command
+/lang [ru|en|kk] - Bot language for this chat
+/help - Show this help
+
+💡 <b>How to use:</b>
+1. Send /info
+2. Choose a computer from the list
+3. Get the full report
+
+Scheduled reports: %s`,
+}
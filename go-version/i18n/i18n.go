@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"system-monitor/monitor"
+	"time"
+)
+
+// Default is the language used when a message is missing from a catalog
+const Default = "ru"
+
+// catalogs maps a language code to its messages
+var catalogs = map[string]map[string]string{
+	"ru": ru,
+	"en": en,
+	"kk": kk,
+}
+
+// location is the time zone dates are shown in
+var (
+	location    = time.Local
+	locationMux sync.RWMutex
+)
+
+// SetLocation sets the time zone used by DateTime
+func SetLocation(loc *time.Location) {
+	locationMux.Lock()
+	defer locationMux.Unlock()
+
+	location = loc
+}
+
+// Localizer formats messages, byte sizes and dates in one language
+type Localizer struct {
+	lang string
+}
+
+// New returns a localizer for lang, falling back to the default language
+// when lang is not supported
+func New(lang string) *Localizer {
+	if !Supported(lang) {
+		lang = Default
+	}
+	return &Localizer{lang: lang}
+}
+
+// Supported reports whether a language has a catalog
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Languages returns the supported language codes
+func Languages() []string {
+	var langs []string
+	for lang := range catalogs {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// Lang returns the language code of the localizer
+func (l *Localizer) Lang() string {
+	return l.lang
+}
+
+// T returns the message for key formatted with args
+func (l *Localizer) T(key string, args ...interface{}) string {
+	message, ok := catalogs[l.lang][key]
+	if !ok {
+		message, ok = catalogs[Default][key]
+	}
+	if !ok {
+		message = key
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Bytes formats a byte count with localized units
+func (l *Localizer) Bytes(bytes uint64) string {
+	return monitor.FormatBytesUnits(bytes, strings.Split(l.T("units.bytes"), ","))
+}
+
+// DateTime formats a timestamp in the language's date format and the
+// configured time zone
+func (l *Localizer) DateTime(t time.Time) string {
+	locationMux.RLock()
+	defer locationMux.RUnlock()
+
+	return t.In(location).Format(l.T("format.datetime"))
+}
+
+// Duration formats a duration rounded to minutes, e.g. "1 ч 5 мин"
+func (l *Localizer) Duration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", days, l.T("units.days")))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", hours, l.T("units.hours")))
+	}
+	if minutes > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", minutes, l.T("units.minutes")))
+	}
+	return strings.Join(parts, " ")
+}
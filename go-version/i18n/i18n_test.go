@@ -0,0 +1,51 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+// verbs matches the formatting verbs of a message
+var verbs = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalogsMatch(t *testing.T) {
+	for lang, catalog := range catalogs {
+		if lang == Default {
+			continue
+		}
+
+		for key, message := range catalogs[Default] {
+			translated, ok := catalog[key]
+			if !ok {
+				t.Errorf("%s: missing %q", lang, key)
+				continue
+			}
+			want, got := verbs.FindAllString(message, -1), verbs.FindAllString(translated, -1)
+			if len(want) != len(got) {
+				t.Errorf("%s: %q has verbs %q, want %q", lang, key, got, want)
+				continue
+			}
+			for i := range want {
+				if want[i] != got[i] {
+					t.Errorf("%s: %q has verbs %q, want %q", lang, key, got, want)
+					break
+				}
+			}
+		}
+
+		for key := range catalog {
+			if _, ok := catalogs[Default][key]; !ok {
+				t.Errorf("%s: %q is not in the default catalog", lang, key)
+			}
+		}
+	}
+}
+
+func TestFallback(t *testing.T) {
+	if got := New("fr").Lang(); got != Default {
+		t.Errorf("unsupported language resolved to %q, want %q", got, Default)
+	}
+	if got := New("en").T("no.such.key"); got != "no.such.key" {
+		t.Errorf("missing key rendered as %q", got)
+	}
+}
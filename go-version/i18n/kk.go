@@ -0,0 +1,167 @@
+package i18n
+
+var kk = map[string]string{
+	"units.bytes":     "Б,КБ,МБ,ГБ,ТБ,ПБ",
+	"units.days":      "күн",
+	"units.hours":     "сағ",
+	"units.minutes":   "мин",
+	"format.datetime": "02.01.2006 15:04:05",
+
+	"report.title":           "Жүйе күйі туралы есеп",
+	"report.computer":        "Компьютер:",
+	"report.time":            "Уақыты:",
+	"report.network":         "Желі:",
+	"report.hostname":        "Хост атауы: %s",
+	"report.local_ip":        "Жергілікті IP: %s",
+	"report.external_ip":     "Сыртқы IP: %s",
+	"report.cpu":             "Процессор:",
+	"report.cores":           "Ядролар: %d",
+	"report.load":            "Жүктеме: %.1f%%",
+	"report.memory":          "Жад:",
+	"report.total":           "Барлығы: %s",
+	"report.used":            "Пайдаланылған: %s (%.1f%%)",
+	"report.available":       "Қолжетімді: %s",
+	"report.free":            "Бос: %s",
+	"report.disks":           "Дискілер:",
+	"report.trend":           "24 сағаттағы өзгерістер:",
+	"report.trend_cpu":       "CPU: орташа %.1f%%, ең жоғары %.1f%%",
+	"report.trend_memory":    "Жад: орташа %.1f%%, ең жоғары %.1f%%",
+	"report.forecast":        "Дискілердің толу болжамы:",
+	"report.forecast_line":   "%s: ~%.0f күннен кейін толады (%s/күн)",
+	"report.top_cpu":         "Ең белсенді процестер (CPU):",
+	"report.top_cpu_line":    "%s: %.1f%% (PID: %d)",
+	"report.top_memory":      "Ең белсенді процестер (жад):",
+	"report.top_memory_line": "%s: %.0f МБ (%.1f%%)",
+	"report.services":        "Қызметтер:",
+	"report.service_line":    "%s %s: %s",
+	"report.errors":          "Деректерді жинау қателері:",
+
+	"alert.firing":          "🚨 <b>Дабыл: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\n%s: %s (шек %s %s)",
+	"alert.repeat":          "🔁 <b>Дабыл жалғасуда: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\n%s: %s (шек %s %s)\nҰзақтығы: %s",
+	"alert.resolved":        "✅ <b>Қалыпты: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\n%s: %s\nҰзақтығы: %s",
+	"alert.metric.cpu":      "CPU жүктемесі",
+	"alert.metric.memory":   "Жад",
+	"alert.metric.disk":     "Диск %s",
+	"alert.metric.forecast": "Диск %s толуына дейін",
+	"alert.value.days":      "%.1f күн",
+	"alert.value.no_growth": "∞ (өсу байқалмады)",
+	"alert.service_down":    "🚨 <b>Дабыл: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\n%s қызметі тоқтады (%s)",
+	"alert.service_repeat":  "🔁 <b>Дабыл жалғасуда: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\n%s қызметі тоқтады (%s)\nҰзақтығы: %s",
+	"alert.service_up":      "✅ <b>Қалыпты: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\n%s қызметі қайта жұмыс істеп тұр\nҰзақтығы: %s",
+
+	"bot.unknown_command":     "Белгісіз команда. Командалар тізімі үшін /help пайдаланыңыз.",
+	"bot.no_computers":        "Қолжетімді компьютерлер жоқ",
+	"bot.choose_computer":     "Компьютерді таңдаңыз:",
+	"bot.no_registered":       "Тіркелген компьютерлер жоқ",
+	"bot.status_title":        "📊 <b>Компьютерлер күйі:</b>",
+	"bot.online":              "Online",
+	"bot.offline":             "Offline",
+	"bot.last_seen":           "Соңғы белсенділік: %s (%s бұрын)",
+	"bot.report_error":        "Есепті құру қатесі: %s",
+	"bot.no_history":          "Метрикалар тарихы қолжетімсіз",
+	"bot.bad_period":          "Кезең қате. Мысалдар: /chart 1h, /chart 24h, /chart 7d",
+	"bot.history_error":       "Тарихты оқу қатесі: %s",
+	"bot.not_enough_data":     "%s кезеңіне график үшін деректер жеткіліксіз",
+	"bot.chart_caption":       "📈 <b>%s</b> — соңғы %s",
+	"bot.lang_current":        "Ағымдағы тіл: %s\nҚолжетімді: %s\n\nҚолдану: /lang ru",
+	"bot.lang_unknown":        "Белгісіз тіл %s. Қолжетімді: %s",
+	"bot.lang_set":            "Тіл өзгертілді: қазақша",
+	"bot.lang_error":          "Тілді сақтау мүмкін болмады: %s",
+	"hub.offline":             "❌ %s желіде жоқ, әзірге есептер жоқ",
+	"hub.stale_report":        "⚠️ %s желіде жоқ. %s уақытындағы соңғы есеп:",
+	"bot.request_sent":        "⏳ Сұрау %s компьютеріне жіберілді, есепті күтіңіз",
+	"bot.request_error":       "Есепті сұрау мүмкін болмады: %s",
+	"hub.went_offline":        "❌ <b>%s</b> жауап бермейді (%s бойы сигнал жоқ)",
+	"hub.back_online":         "✅ <b>%s</b> қайтадан желіде (%s байланыс болмады)",
+	"outbox.delayed":          "⏳ <i>Кешігіп жеткізілді, жасалған уақыты %s</i>",
+	"app.stopping":            "⏹ <b>%s</b>: мониторинг тоқтатылды",
+	"app.reloaded":            "🔄 <b>%s</b>: конфигурация жаңартылды\n%s",
+	"app.reload_failed":       "⚠️ <b>%s</b>: жаңа конфигурация қолданылмады, ескісімен жұмыс жалғасуда\n<code>%s</code>",
+	"bot.summary":             "CPU %.0f%% · RAM %.0f%% · Диск %.0f%% · жұмыс уақыты %s",
+	"bot.no_match":            "Сүзгіге сәйкес компьютерлер жоқ",
+	"bot.computers_title":     "🖥 <b>Компьютерлер:</b>",
+	"bot.computer_group":      "Топ: %s",
+	"bot.computer_tags":       "Тегтер: %s",
+	"bot.computer_owner":      "Иесі: %s",
+	"bot.computer_seen":       "Соңғы белсенділік: %s",
+	"bot.unknown_computer":    "%s компьютері табылмады. Тізім: /computers",
+	"bot.rename_usage":        "Пайдалану: /rename &lt;id&gt; &lt;жаңа атау&gt;",
+	"bot.renamed":             "✏️ %s компьютерінің атауы <b>%s</b> болып өзгертілді",
+	"bot.forget_usage":        "Пайдалану: /forget &lt;id&gt;",
+	"bot.forget_local":        "Бот жұмыс істеп тұрған компьютерді жою мүмкін емес",
+	"bot.forget_error":        "Компьютерді жою сәтсіз: %s",
+	"bot.forgotten":           "🗑 %s тізімнен жойылды",
+	"bot.access_denied":       "⛔ Қолжетімділік жоқ. Сіздің ID: <code>%d</code> - оны әкімшіге жіберіңіз",
+	"bot.access_insufficient": "⛔ %s үшін құқық жеткіліксіз (%s рөлі қажет)",
+	"bot.proc_error":          "Процестер тізімін алу қатесі: %s",
+	"bot.top_empty":           "Процестер табылмады",
+	"bot.top_title":           "⚙️ <b>%s процестері</b> (%d / %d):",
+	"bot.top_line":            "<code>%d</code> %s — CPU %.1f%% · RAM %.1f MB",
+	"bot.top_hint":            "Толығырақ: /proc &lt;pid&gt;, тоқтату: /kill &lt;pid&gt;",
+	"bot.proc_usage":          "Пайдалану: /proc &lt;pid&gt;",
+	"bot.proc_not_found":      "%d процесі табылмады",
+	"bot.proc_title":          "⚙️ <b>%s</b> (PID %d)",
+	"bot.proc_usage_line":     "CPU %.1f%% · RAM %.1f MB (%.1f%%)",
+	"bot.proc_user":           "Пайдаланушы: %s",
+	"bot.proc_started":        "Іске қосылған: %s",
+	"bot.proc_threads":        "Ағындар: %d · ата-процесс: %d",
+	"bot.proc_status":         "Күйі: %s",
+	"bot.proc_exe":            "Файл: <code>%s</code>",
+	"bot.proc_cmdline":        "Командалық жол: <code>%s</code>",
+	"bot.proc_files":          "Ашық файлдар (%d):",
+	"bot.proc_files_more":     "…және тағы %d",
+	"bot.kill_usage":          "Пайдалану: /kill &lt;pid&gt;",
+	"bot.kill_confirm":        "⚠️ <b>Процесті тоқтату керек пе?</b>",
+	"bot.kill_terminate":      "Аяқтау",
+	"bot.kill_force":          "Мәжбүрлеп тоқтату",
+	"bot.kill_cancel":         "Болдырмау",
+	"bot.kill_cancelled":      "Процесті тоқтату болдырылмады",
+	"bot.kill_expired":        "Растау ескірді, /kill командасын қайта жіберіңіз",
+	"bot.kill_error":          "%d процесін тоқтату мүмкін болмады: %s",
+	"bot.terminated":          "✅ %d процесіне аяқтау сигналы жіберілді",
+	"bot.killed":              "✅ %d процесі мәжбүрлеп тоқтатылды",
+	"bot.services_none":       "Бақыланатын қызметтер жоқ. Оларды config.json ішіндегі services параметріне қосыңыз",
+	"bot.services_title":      "🧩 <b>%s қызметтері:</b>",
+	"bot.services_hint":       "Қайта іске қосу: /restart &lt;атауы&gt;",
+	"bot.restart_usage":       "Пайдалану: /restart &lt;қызмет атауы&gt;",
+	"bot.restart_unknown":     "%s қызметі бақыланбайды. Тізім: /services",
+	"bot.restart_button":      "Қайта іске қосу",
+	"bot.restart_confirm":     "⚠️ <b>%s қызметін %s компьютерінде қайта іске қосу керек пе?</b>",
+	"bot.restart_cancelled":   "Қайта іске қосу болдырылмады",
+	"bot.restart_error":       "%s қайта іске қосу мүмкін болмады: %s",
+	"bot.restarted":           "✅ %s қызметі қайта іске қосылды",
+	"bot.run_title":           "▶️ <b>Қолжетімді командалар:</b>",
+	"bot.run_none":            "Іске қосуға болатын командалар жоқ",
+	"bot.run_unknown":         "%s командасы бапталмаған. Тізім: /run",
+	"bot.run_result":          "▶️ <b>%s</b>: шығу коды %d, %s",
+	"bot.run_timeout":         "⏱ <b>%s</b> уақыт шегі бойынша тоқтатылды (%s)",
+	"bot.run_error":           "%s іске қосу мүмкін болмады: %s",
+	"bot.run_no_output":       "(шығыс жоқ)",
+	"bot.run_truncated":       "… шығыс %d КБ дейін қысқартылды",
+	"bot.schedule_at":         "күн сайын %s",
+	"bot.schedule_every":      "әр %s сайын",
+	"bot.schedule_cron":       "<code>%s</code> кестесі бойынша",
+	"bot.help": `📖 <b>Қолжетімді командалар:</b>
+
+/info - Компьютер туралы толық ақпарат
+/status [tag:кеңсе] - Компьютерлердің қысқаша күйі
+/computers [group:қойма] - Тегтері мен топтары бар компьютерлер тізімі
+/rename &lt;id&gt; &lt;атау&gt; - Компьютердің атауын өзгерту
+/forget &lt;id&gt; - Компьютерді тізімнен жою
+/chart [1h|24h|7d] - CPU, жад және диск графиктері
+/top [cpu|mem|name] [N] [атау] - Осы компьютердің процестері
+/proc &lt;pid&gt; - Процесс туралы толығырақ
+/kill &lt;pid&gt; - Процесті тоқтату (растаумен)
+/services - Бақыланатын қызметтердің күйі
+/restart &lt;атауы&gt; - Қызметті қайта іске қосу (растаумен)
+/run [атауы] - Рұқсат етілген команданы іске қосу
+/lang [ru|en|kk] - Осы чаттағы бот тілі
+/help - Осы анықтаманы көрсету
+
+💡 <b>Қалай пайдалану:</b>
+1. /info жіберіңіз
+2. Тізімнен компьютерді таңдаңыз
+3. Толық есепті алыңыз
+
+Автоматты есептер: %s`,
+}
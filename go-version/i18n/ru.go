@@ -0,0 +1,167 @@
+package i18n
+
+var ru = map[string]string{
+	"units.bytes":     "Б,КБ,МБ,ГБ,ТБ,ПБ",
+	"units.days":      "дн.",
+	"units.hours":     "ч",
+	"units.minutes":   "мин",
+	"format.datetime": "02.01.2006 15:04:05",
+
+	"report.title":           "Отчет о состоянии системы",
+	"report.computer":        "Компьютер:",
+	"report.time":            "Время:",
+	"report.network":         "Сеть:",
+	"report.hostname":        "Имя хоста: %s",
+	"report.local_ip":        "Локальный IP: %s",
+	"report.external_ip":     "Внешний IP: %s",
+	"report.cpu":             "Процессор:",
+	"report.cores":           "Ядер: %d",
+	"report.load":            "Загрузка: %.1f%%",
+	"report.memory":          "Память:",
+	"report.total":           "Всего: %s",
+	"report.used":            "Использовано: %s (%.1f%%)",
+	"report.available":       "Доступно: %s",
+	"report.free":            "Свободно: %s",
+	"report.disks":           "Диски:",
+	"report.trend":           "Изменения за 24 ч:",
+	"report.trend_cpu":       "CPU: среднее %.1f%%, пик %.1f%%",
+	"report.trend_memory":    "Память: среднее %.1f%%, пик %.1f%%",
+	"report.forecast":        "Прогноз заполнения дисков:",
+	"report.forecast_line":   "%s: заполнится через ~%.0f дн. (%s/день)",
+	"report.top_cpu":         "Топ процессы (CPU):",
+	"report.top_cpu_line":    "%s: %.1f%% (PID: %d)",
+	"report.top_memory":      "Топ процессы (Память):",
+	"report.top_memory_line": "%s: %.0f МБ (%.1f%%)",
+	"report.services":        "Службы:",
+	"report.service_line":    "%s %s: %s",
+	"report.errors":          "Ошибки сбора данных:",
+
+	"alert.firing":          "🚨 <b>Тревога: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\n%s: %s (порог %s %s)",
+	"alert.repeat":          "🔁 <b>Тревога продолжается: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\n%s: %s (порог %s %s)\nДлительность: %s",
+	"alert.resolved":        "✅ <b>Норма: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\n%s: %s\nДлительность: %s",
+	"alert.metric.cpu":      "Загрузка CPU",
+	"alert.metric.memory":   "Память",
+	"alert.metric.disk":     "Диск %s",
+	"alert.metric.forecast": "Диск %s заполнится через",
+	"alert.value.days":      "%.1f дн.",
+	"alert.value.no_growth": "∞ (рост не обнаружен)",
+	"alert.service_down":    "🚨 <b>Тревога: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\nСлужба %s остановлена (%s)",
+	"alert.service_repeat":  "🔁 <b>Тревога продолжается: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\nСлужба %s остановлена (%s)\nДлительность: %s",
+	"alert.service_up":      "✅ <b>Норма: %s</b>\n\n🖥️ <b>Компьютер:</b> %s\nСлужба %s снова работает\nДлительность: %s",
+
+	"bot.unknown_command":     "Неизвестная команда. Используйте /help для списка команд.",
+	"bot.no_computers":        "Нет доступных компьютеров",
+	"bot.choose_computer":     "Выберите компьютер:",
+	"bot.no_registered":       "Нет зарегистрированных компьютеров",
+	"bot.status_title":        "📊 <b>Статус компьютеров:</b>",
+	"bot.online":              "Online",
+	"bot.offline":             "Offline",
+	"bot.last_seen":           "Последняя активность: %s (%s назад)",
+	"bot.report_error":        "Ошибка создания отчета: %s",
+	"bot.no_history":          "История метрик недоступна",
+	"bot.bad_period":          "Неверный период. Примеры: /chart 1h, /chart 24h, /chart 7d",
+	"bot.history_error":       "Ошибка чтения истории: %s",
+	"bot.not_enough_data":     "Недостаточно данных для графика за %s",
+	"bot.chart_caption":       "📈 <b>%s</b> — за %s",
+	"bot.lang_current":        "Текущий язык: %s\nДоступны: %s\n\nИспользование: /lang en",
+	"bot.lang_unknown":        "Неизвестный язык %s. Доступны: %s",
+	"bot.lang_set":            "Язык изменен: русский",
+	"bot.lang_error":          "Не удалось сохранить язык: %s",
+	"hub.offline":             "❌ %s не в сети, отчетов пока нет",
+	"hub.stale_report":        "⚠️ %s не в сети. Последний отчет от %s:",
+	"bot.request_sent":        "⏳ Запрос отправлен на %s, ожидайте отчет",
+	"bot.request_error":       "Не удалось запросить отчет: %s",
+	"hub.went_offline":        "❌ <b>%s</b> перестал отвечать (нет сигнала %s)",
+	"hub.back_online":         "✅ <b>%s</b> снова в сети (не было связи %s)",
+	"outbox.delayed":          "⏳ <i>Доставлено с задержкой, создано %s</i>",
+	"app.stopping":            "⏹ <b>%s</b>: мониторинг остановлен",
+	"app.reloaded":            "🔄 <b>%s</b>: конфигурация обновлена\n%s",
+	"app.reload_failed":       "⚠️ <b>%s</b>: новая конфигурация не применена, работа продолжается со старой\n<code>%s</code>",
+	"bot.summary":             "CPU %.0f%% · RAM %.0f%% · Диск %.0f%% · работает %s",
+	"bot.no_match":            "Нет компьютеров, подходящих под фильтр",
+	"bot.computers_title":     "🖥 <b>Компьютеры:</b>",
+	"bot.computer_group":      "Группа: %s",
+	"bot.computer_tags":       "Теги: %s",
+	"bot.computer_owner":      "Владелец: %s",
+	"bot.computer_seen":       "Последняя активность: %s",
+	"bot.unknown_computer":    "Компьютер %s не найден. Список: /computers",
+	"bot.rename_usage":        "Использование: /rename &lt;id&gt; &lt;новое имя&gt;",
+	"bot.renamed":             "✏️ Компьютер %s переименован в <b>%s</b>",
+	"bot.forget_usage":        "Использование: /forget &lt;id&gt;",
+	"bot.forget_local":        "Нельзя удалить компьютер, на котором работает бот",
+	"bot.forget_error":        "Не удалось удалить компьютер: %s",
+	"bot.forgotten":           "🗑 %s удален из списка",
+	"bot.access_denied":       "⛔ Доступ запрещен. Ваш ID: <code>%d</code> - передайте его администратору",
+	"bot.access_insufficient": "⛔ Недостаточно прав для %s (нужна роль %s)",
+	"bot.proc_error":          "Ошибка получения списка процессов: %s",
+	"bot.top_empty":           "Процессы не найдены",
+	"bot.top_title":           "⚙️ <b>Процессы %s</b> (%d из %d):",
+	"bot.top_line":            "<code>%d</code> %s — CPU %.1f%% · RAM %.1f MB",
+	"bot.top_hint":            "Подробнее: /proc &lt;pid&gt;, остановить: /kill &lt;pid&gt;",
+	"bot.proc_usage":          "Использование: /proc &lt;pid&gt;",
+	"bot.proc_not_found":      "Процесс %d не найден",
+	"bot.proc_title":          "⚙️ <b>%s</b> (PID %d)",
+	"bot.proc_usage_line":     "CPU %.1f%% · RAM %.1f MB (%.1f%%)",
+	"bot.proc_user":           "Пользователь: %s",
+	"bot.proc_started":        "Запущен: %s",
+	"bot.proc_threads":        "Потоков: %d · родитель: %d",
+	"bot.proc_status":         "Состояние: %s",
+	"bot.proc_exe":            "Файл: <code>%s</code>",
+	"bot.proc_cmdline":        "Командная строка: <code>%s</code>",
+	"bot.proc_files":          "Открытые файлы (%d):",
+	"bot.proc_files_more":     "…и еще %d",
+	"bot.kill_usage":          "Использование: /kill &lt;pid&gt;",
+	"bot.kill_confirm":        "⚠️ <b>Остановить процесс?</b>",
+	"bot.kill_terminate":      "Завершить",
+	"bot.kill_force":          "Убить принудительно",
+	"bot.kill_cancel":         "Отмена",
+	"bot.kill_cancelled":      "Остановка процесса отменена",
+	"bot.kill_expired":        "Подтверждение устарело, отправьте /kill еще раз",
+	"bot.kill_error":          "Не удалось остановить процесс %d: %s",
+	"bot.terminated":          "✅ Процессу %d отправлен сигнал завершения",
+	"bot.killed":              "✅ Процесс %d принудительно остановлен",
+	"bot.services_none":       "Нет отслеживаемых служб. Добавьте их в параметр services в config.json",
+	"bot.services_title":      "🧩 <b>Службы %s:</b>",
+	"bot.services_hint":       "Перезапуск: /restart &lt;имя&gt;",
+	"bot.restart_usage":       "Использование: /restart &lt;имя службы&gt;",
+	"bot.restart_unknown":     "Служба %s не отслеживается. Список: /services",
+	"bot.restart_button":      "Перезапустить",
+	"bot.restart_confirm":     "⚠️ <b>Перезапустить службу %s на %s?</b>",
+	"bot.restart_cancelled":   "Перезапуск отменен",
+	"bot.restart_error":       "Не удалось перезапустить %s: %s",
+	"bot.restarted":           "✅ Служба %s перезапущена",
+	"bot.run_title":           "▶️ <b>Доступные команды:</b>",
+	"bot.run_none":            "Нет команд, доступных для запуска",
+	"bot.run_unknown":         "Команда %s не настроена. Список: /run",
+	"bot.run_result":          "▶️ <b>%s</b>: код выхода %d, %s",
+	"bot.run_timeout":         "⏱ <b>%s</b> остановлена по таймауту (%s)",
+	"bot.run_error":           "Не удалось запустить %s: %s",
+	"bot.run_no_output":       "(нет вывода)",
+	"bot.run_truncated":       "… вывод обрезан до %d КБ",
+	"bot.schedule_at":         "ежедневно в %s",
+	"bot.schedule_every":      "каждые %s",
+	"bot.schedule_cron":       "по расписанию <code>%s</code>",
+	"bot.help": `📖 <b>Доступные команды:</b>
+
+/info - Получить подробную информацию о компьютере
+/status [tag:офис] - Краткий статус компьютеров
+/computers [group:склад] - Список компьютеров с тегами и группами
+/rename &lt;id&gt; &lt;имя&gt; - Переименовать компьютер
+/forget &lt;id&gt; - Удалить компьютер из списка
+/chart [1h|24h|7d] - Графики CPU, памяти и дисков
+/top [cpu|mem|name] [N] [имя] - Процессы этого компьютера
+/proc &lt;pid&gt; - Подробности о процессе
+/kill &lt;pid&gt; - Остановить процесс (с подтверждением)
+/services - Состояние отслеживаемых служб
+/restart &lt;имя&gt; - Перезапустить службу (с подтверждением)
+/run [имя] - Запустить команду из списка разрешенных
+/lang [ru|en|kk] - Язык бота для этого чата
+/help - Показать эту справку
+
+💡 <b>Как использовать:</b>
+1. Отправьте /info
+2. Выберите компьютер из списка
+3. Получите полный отчет
+
+Автоматические отчеты: %s`,
+}
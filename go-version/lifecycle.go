@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// group runs the long-lived subsystems under one context. A subsystem that
+// fails stops the others, so the service exits instead of running half-dead.
+type group struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	err    error
+}
+
+// newGroup creates a group whose context ends with parent
+func newGroup(parent context.Context) *group {
+	ctx, cancel := context.WithCancel(parent)
+	return &group{ctx: ctx, cancel: cancel}
+}
+
+// Go starts fn in a goroutine. An error returned by fn is logged and stops
+// the whole group.
+func (g *group) Go(name string, fn func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(g.ctx); err != nil {
+			log.Printf("Ошибка %s: %v", name, err)
+			g.mu.Lock()
+			if g.err == nil {
+				g.err = err
+			}
+			g.mu.Unlock()
+			g.cancel()
+		}
+	}()
+}
+
+// Done is closed when the group is asked to stop
+func (g *group) Done() <-chan struct{} {
+	return g.ctx.Done()
+}
+
+// Stop cancels the group and waits up to timeout for its goroutines to
+// return. It reports whether all of them did.
+func (g *group) Stop(timeout time.Duration) bool {
+	g.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// Err returns the first error returned by a subsystem, if any
+func (g *group) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.err
+}
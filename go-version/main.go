@@ -1,17 +1,31 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"log"
 	"os"
-	"system-monitor/bot"
+	"os/signal"
+	"syscall"
 	"system-monitor/config"
+	"system-monitor/i18n"
+	"system-monitor/report"
 	"system-monitor/scheduler"
+	"system-monitor/telegram"
+	"time"
+
+	// Time zone names must resolve on Windows, which has no zoneinfo database
+	_ "time/tzdata"
 )
 
 const (
 	version = "1.0.0"
 	author  = "Serik Muftakhidinov"
+
+	// shutdownTimeout bounds how long subsystems may take to stop
+	shutdownTimeout = 15 * time.Second
+	// flushTimeout bounds the last delivery of queued messages on shutdown
+	flushTimeout = 10 * time.Second
 )
 
 func main() {
@@ -19,6 +33,8 @@ func main() {
 	testMode := flag.Bool("test", false, "Run in test mode (send report immediately)")
 	configPath := flag.String("config", "config.json", "Path to config file")
 	showVersion := flag.Bool("version", false, "Show version information")
+	printMode := flag.Bool("print", false, "Print report to stdout instead of sending it")
+	format := flag.String("format", "", "Output format for -print: text, markdown, json or html")
 	flag.Parse()
 
 	if *showVersion {
@@ -26,6 +42,17 @@ func main() {
 		return
 	}
 
+	// Print report and exit, no bot token required
+	if *printMode || *format != "" {
+		if *format == "" {
+			*format = report.FormatText
+		}
+		if err := runPrint(*configPath, *format); err != nil {
+			log.Fatalf("Ошибка вывода отчета: %v", err)
+		}
+		return
+	}
+
 	// Load configuration
 	cfg, err := config.LoadConfig(*configPath)
 	if err != nil {
@@ -44,30 +71,56 @@ func main() {
 	log.Printf("System Monitor v%s starting...", version)
 	log.Printf("Computer: %s (%s)", cfg.ComputerName, cfg.ComputerID)
 
+	// Show dates in the configured time zone
+	i18n.SetLocation(cfg.Location())
+
+	// Load per-chat language overrides
+	if err := i18n.LoadChatLanguages(cfg.DataPath("chat_languages.json")); err != nil {
+		log.Printf("Ошибка загрузки языков чатов: %v", err)
+	}
+
 	// Run in test or service mode
 	if *testMode {
-		if err := scheduler.RunTest(cfg); err != nil {
+		if err := scheduler.RunTest(cfg, openStore(cfg), telegram.NewClient(cfg)); err != nil {
 			log.Fatalf("Ошибка в тестовом режиме: %v", err)
 		}
 		log.Println("Тестовая отправка завершена")
 		return
 	}
 
-	// Run scheduler in background
-	go func() {
-		if err := scheduler.Run(cfg); err != nil {
-			log.Fatalf("Ошибка запуска планировщика: %v", err)
+	// Stop on SIGINT or SIGTERM; every subsystem runs under this context
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	reloads := watchConfig(ctx, *configPath)
+
+	svc := startService(ctx, cfg)
+	var reloadErr error
+	for running := true; running; {
+		select {
+		case <-svc.services.Done():
+			running = false
+		case <-reloads:
+			if svc, reloadErr = reload(ctx, svc, *configPath); reloadErr != nil {
+				log.Printf("Ошибка перезагрузки конфигурации: %v", reloadErr)
+				running = false
+			}
 		}
-	}()
-
-	// Run polling if enabled
-	if cfg.EnablePolling {
-		log.Println("Interactive mode enabled")
-		poller := bot.NewPoller(cfg)
-		poller.StartPolling()
-	} else {
-		log.Println("Polling disabled, running in scheduled mode only")
-		// Keep running
-		select {}
 	}
+
+	log.Println("Остановка сервиса...")
+	svc.stop()
+
+	// Deliver what is left in the queue, with the stop notice last
+	if svc.cfg.NotifyOnStop {
+		svc.notify("app.stopping", svc.cfg.ComputerName)
+	}
+	flushCtx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	svc.queue.Flush(flushCtx)
+
+	if reloadErr != nil || svc.services.Err() != nil {
+		log.Println("Сервис остановлен из-за ошибки")
+		os.Exit(1)
+	}
+	log.Println("Сервис остановлен")
 }
@@ -0,0 +1,104 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+// minCPUWindow is the shortest period CPU utilization is measured over;
+// shorter windows are dominated by scheduling noise
+const minCPUWindow = time.Second
+
+// CPUSampler measures CPU utilization since its own previous measurement.
+// Each consumer keeps its own sampler, so consumers on different schedules
+// do not shorten each other's measurement window.
+type CPUSampler struct {
+	mu     sync.Mutex
+	last   cpu.TimesStat
+	lastAt time.Time
+}
+
+// NewCPUSampler creates a sampler whose first window starts now
+func NewCPUSampler() *CPUSampler {
+	s := &CPUSampler{}
+	if times, err := totalTimes(); err == nil {
+		s.last, s.lastAt = times, time.Now()
+	}
+	return s
+}
+
+// Info returns the CPU count and the utilization since the previous call,
+// waiting until at least minCPUWindow has passed since then
+func (s *CPUSampler) Info() (*CPUInfo, error) {
+	count, err := cpu.Counts(true)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.lastAt.IsZero() {
+		if s.last, err = totalTimes(); err != nil {
+			return nil, err
+		}
+		s.lastAt = time.Now()
+	}
+	if wait := minCPUWindow - time.Since(s.lastAt); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	times, err := totalTimes()
+	if err != nil {
+		return nil, err
+	}
+	percent := busyPercent(s.last, times)
+	s.last, s.lastAt = times, time.Now()
+
+	return &CPUInfo{
+		Count:   count,
+		Percent: percent,
+	}, nil
+}
+
+// totalTimes returns the CPU times of all CPUs combined
+func totalTimes() (cpu.TimesStat, error) {
+	times, err := cpu.Times(false)
+	if err != nil {
+		return cpu.TimesStat{}, err
+	}
+	if len(times) == 0 {
+		return cpu.TimesStat{}, fmt.Errorf("no cpu times")
+	}
+	return times[0], nil
+}
+
+// busyPercent returns the share of time the CPUs were busy between two
+// readings, computed as gopsutil does for cpu.Percent
+func busyPercent(t1, t2 cpu.TimesStat) float64 {
+	t1All, t1Busy := busyTimes(t1)
+	t2All, t2Busy := busyTimes(t2)
+
+	if t2Busy <= t1Busy {
+		return 0
+	}
+	if t2All <= t1All {
+		return 100
+	}
+	return math.Min(100, math.Max(0, (t2Busy-t1Busy)/(t2All-t1All)*100))
+}
+
+// busyTimes returns the total and busy time of a reading; guest time is
+// already counted in user time on Linux
+func busyTimes(t cpu.TimesStat) (total, busy float64) {
+	total = t.Total()
+	if runtime.GOOS == "linux" {
+		total -= t.Guest + t.GuestNice
+	}
+	return total, total - t.Idle - t.Iowait
+}
@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"math"
+	"testing"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+)
+
+func TestBusyPercent(t *testing.T) {
+	base := cpu.TimesStat{User: 100, System: 50, Idle: 800, Iowait: 50}
+
+	tests := []struct {
+		name string
+		next cpu.TimesStat
+		want float64
+	}{
+		{"idle", cpu.TimesStat{User: 100, System: 50, Idle: 900, Iowait: 50}, 0},
+		{"half busy", cpu.TimesStat{User: 150, System: 50, Idle: 850, Iowait: 50}, 50},
+		{"fully busy", cpu.TimesStat{User: 180, System: 70, Idle: 800, Iowait: 50}, 100},
+		{"iowait is idle", cpu.TimesStat{User: 125, System: 50, Idle: 800, Iowait: 125}, 25},
+		{"no time passed", base, 0},
+		{"counters reset", cpu.TimesStat{User: 10, System: 5, Idle: 80, Iowait: 5}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := busyPercent(base, tt.next); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("busyPercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCPUSamplersAreIndependent(t *testing.T) {
+	first, second := NewCPUSampler(), NewCPUSampler()
+
+	// A call on one sampler must not move the window of the other
+	if _, err := first.Info(); err != nil {
+		t.Skipf("cpu times not available: %v", err)
+	}
+	before := second.lastAt
+	if _, err := first.Info(); err != nil {
+		t.Fatal(err)
+	}
+	if second.lastAt != before {
+		t.Errorf("second sampler window moved from %v to %v", before, second.lastAt)
+	}
+
+	if _, err := second.Info(); err != nil {
+		t.Fatal(err)
+	}
+	if !second.lastAt.After(before) {
+		t.Errorf("second sampler window did not move")
+	}
+}
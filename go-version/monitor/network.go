@@ -5,17 +5,49 @@ import (
 	"net/http"
 	"io"
 	"time"
+
+	psnet "github.com/shirou/gopsutil/v3/net"
 )
 
 // IPInfo contains network information
 type IPInfo struct {
-	Hostname   string
-	LocalIP    string
-	ExternalIP string
+	Hostname   string `json:"hostname"`
+	LocalIP    string `json:"local_ip"`
+	ExternalIP string `json:"external_ip"`
+}
+
+// InterfaceCounters contains the traffic counters of a network interface
+// since boot
+type InterfaceCounters struct {
+	Name        string `json:"name"`
+	BytesSent   uint64 `json:"bytes_sent"`
+	BytesRecv   uint64 `json:"bytes_recv"`
+	PacketsSent uint64 `json:"packets_sent"`
+	PacketsRecv uint64 `json:"packets_recv"`
+	ErrorsIn    uint64 `json:"errors_in"`
+	ErrorsOut   uint64 `json:"errors_out"`
+	DropsIn     uint64 `json:"drops_in"`
+	DropsOut    uint64 `json:"drops_out"`
 }
 
 // GetIPInfo retrieves network information
 func GetIPInfo() (*IPInfo, error) {
+	info := GetLocalIPInfo()
+
+	// Get external IP
+	externalIP, err := GetExternalIP()
+	if err != nil {
+		info.ExternalIP = "N/A"
+	} else {
+		info.ExternalIP = externalIP
+	}
+
+	return info, nil
+}
+
+// GetLocalIPInfo retrieves the hostname and local IP without querying the
+// external IP service
+func GetLocalIPInfo() *IPInfo {
 	info := &IPInfo{}
 
 	// Get hostname
@@ -28,15 +60,32 @@ func GetIPInfo() (*IPInfo, error) {
 		info.LocalIP = "N/A"
 	}
 
-	// Get external IP
-	externalIP, err := GetExternalIP()
+	return info
+}
+
+// GetInterfaceCounters retrieves traffic counters of every network interface
+func GetInterfaceCounters() ([]InterfaceCounters, error) {
+	stats, err := psnet.IOCounters(true)
 	if err != nil {
-		info.ExternalIP = "N/A"
-	} else {
-		info.ExternalIP = externalIP
+		return nil, err
 	}
 
-	return info, nil
+	counters := make([]InterfaceCounters, 0, len(stats))
+	for _, stat := range stats {
+		counters = append(counters, InterfaceCounters{
+			Name:        stat.Name,
+			BytesSent:   stat.BytesSent,
+			BytesRecv:   stat.BytesRecv,
+			PacketsSent: stat.PacketsSent,
+			PacketsRecv: stat.PacketsRecv,
+			ErrorsIn:    stat.Errin,
+			ErrorsOut:   stat.Errout,
+			DropsIn:     stat.Dropin,
+			DropsOut:    stat.Dropout,
+		})
+	}
+
+	return counters, nil
 }
 
 // GetHostname returns the system hostname
@@ -0,0 +1,174 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Process sort orders
+const (
+	SortByCPU    = "cpu"
+	SortByMemory = "mem"
+	SortByName   = "name"
+)
+
+// ProcessDetails holds extended information about a single process
+type ProcessDetails struct {
+	ProcessInfo
+	PPID       int32     `json:"ppid"`
+	Exe        string    `json:"exe"`
+	Cmdline    string    `json:"cmdline"`
+	Username   string    `json:"username"`
+	Status     string    `json:"status"`
+	CreateTime time.Time `json:"create_time"`
+	Threads    int32     `json:"threads"`
+	OpenFiles  []string  `json:"open_files"`
+}
+
+// ListProcesses returns all processes whose name can be read
+func ListProcesses() ([]*ProcessInfo, error) {
+	processes, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+
+	var procInfos []*ProcessInfo
+	for _, p := range processes {
+		info, err := processInfo(p)
+		if err != nil {
+			continue
+		}
+		procInfos = append(procInfos, info)
+	}
+
+	return procInfos, nil
+}
+
+// processInfo reads the summary of a process; only the name is required
+func processInfo(p *process.Process) (*ProcessInfo, error) {
+	name, err := p.Name()
+	if err != nil {
+		return nil, err
+	}
+
+	cpuPercent, err := p.CPUPercent()
+	if err != nil {
+		cpuPercent = 0
+	}
+
+	memInfo, err := p.MemoryInfo()
+	memoryMB := 0.0
+	if err == nil && memInfo != nil {
+		memoryMB = float64(memInfo.RSS) / 1024 / 1024
+	}
+
+	memPercent, err := p.MemoryPercent()
+	if err != nil {
+		memPercent = 0
+	}
+
+	return &ProcessInfo{
+		PID:           p.Pid,
+		Name:          name,
+		CPUPercent:    cpuPercent,
+		MemoryMB:      memoryMB,
+		MemoryPercent: memPercent,
+	}, nil
+}
+
+// SortProcesses orders processes by CPU or memory (highest first) or by name
+func SortProcesses(procInfos []*ProcessInfo, by string) {
+	sort.SliceStable(procInfos, func(i, j int) bool {
+		switch by {
+		case SortByMemory:
+			return procInfos[i].MemoryPercent > procInfos[j].MemoryPercent
+		case SortByName:
+			return strings.ToLower(procInfos[i].Name) < strings.ToLower(procInfos[j].Name)
+		default:
+			return procInfos[i].CPUPercent > procInfos[j].CPUPercent
+		}
+	})
+}
+
+// FilterProcesses keeps the processes whose name contains substr, ignoring case
+func FilterProcesses(procInfos []*ProcessInfo, substr string) []*ProcessInfo {
+	substr = strings.ToLower(substr)
+
+	var result []*ProcessInfo
+	for _, p := range procInfos {
+		if strings.Contains(strings.ToLower(p.Name), substr) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// GetProcessDetails retrieves extended information about a process. Fields
+// the current user may not read are left empty.
+func GetProcessDetails(pid int32) (*ProcessDetails, error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find process %d: %w", pid, err)
+	}
+
+	info, err := processInfo(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read process %d: %w", pid, err)
+	}
+
+	details := &ProcessDetails{ProcessInfo: *info}
+	details.PPID, _ = p.Ppid()
+	details.Exe, _ = p.Exe()
+	details.Cmdline, _ = p.Cmdline()
+	details.Username, _ = p.Username()
+	details.Threads, _ = p.NumThreads()
+
+	if status, err := p.Status(); err == nil {
+		details.Status = strings.Join(status, ",")
+	}
+
+	if created, err := p.CreateTime(); err == nil {
+		details.CreateTime = time.UnixMilli(created)
+	}
+
+	if files, err := p.OpenFiles(); err == nil {
+		for _, f := range files {
+			details.OpenFiles = append(details.OpenFiles, f.Path)
+		}
+	}
+
+	return details, nil
+}
+
+// StopProcess terminates a process, or kills it when force is set. The
+// process start time must match created, so that a PID reused by another
+// process since it was looked up is not touched.
+func StopProcess(pid int32, created time.Time, force bool) error {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return fmt.Errorf("failed to find process %d: %w", pid, err)
+	}
+
+	started, err := p.CreateTime()
+	if err != nil {
+		return fmt.Errorf("failed to read process %d: %w", pid, err)
+	}
+	if started != created.UnixMilli() {
+		return fmt.Errorf("process %d has been replaced by another process", pid)
+	}
+
+	if force {
+		err = p.Kill()
+	} else {
+		err = p.Terminate()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to stop process %d: %w", pid, err)
+	}
+
+	return nil
+}
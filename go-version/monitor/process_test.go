@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"testing"
+)
+
+// names returns the names of processes in order
+func names(procs []*ProcessInfo) []string {
+	result := make([]string, len(procs))
+	for i, p := range procs {
+		result[i] = p.Name
+	}
+	return result
+}
+
+func TestSortProcesses(t *testing.T) {
+	procs := []*ProcessInfo{
+		{Name: "nginx", CPUPercent: 5, MemoryPercent: 30},
+		{Name: "Chrome", CPUPercent: 40, MemoryPercent: 10},
+		{Name: "bash", CPUPercent: 1, MemoryPercent: 1},
+	}
+
+	tests := []struct {
+		by   string
+		want []string
+	}{
+		{SortByCPU, []string{"Chrome", "nginx", "bash"}},
+		{SortByMemory, []string{"nginx", "Chrome", "bash"}},
+		{SortByName, []string{"bash", "Chrome", "nginx"}},
+	}
+
+	for _, tt := range tests {
+		SortProcesses(procs, tt.by)
+		got := names(procs)
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("SortProcesses(%s) = %q, want %q", tt.by, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestFilterProcesses(t *testing.T) {
+	procs := []*ProcessInfo{{Name: "chrome"}, {Name: "Chromium"}, {Name: "bash"}}
+
+	got := names(FilterProcesses(procs, "CHROM"))
+	if len(got) != 2 || got[0] != "chrome" || got[1] != "Chromium" {
+		t.Errorf("FilterProcesses() = %q, want chrome and Chromium", got)
+	}
+	if got := FilterProcesses(procs, "python"); len(got) != 0 {
+		t.Errorf("FilterProcesses() = %q, want none", names(got))
+	}
+}
@@ -0,0 +1,32 @@
+package monitor
+
+// ServiceStatus is the state of a systemd unit or Windows service
+type ServiceStatus struct {
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	Running bool   `json:"running"`
+	Error   string `json:"error,omitempty"`
+}
+
+// GetServices returns the status of each named service. A service whose
+// state cannot be read is reported as not running with the error.
+func GetServices(names []string) []ServiceStatus {
+	statuses := make([]ServiceStatus, 0, len(names))
+	for _, name := range names {
+		status := ServiceStatus{Name: name}
+		state, running, err := serviceState(name)
+		if err != nil {
+			status.Error = err.Error()
+		}
+		status.State = state
+		status.Running = running
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// RestartService restarts a service and waits for the service manager to
+// report the result
+func RestartService(name string) error {
+	return restartService(name)
+}
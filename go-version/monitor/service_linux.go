@@ -0,0 +1,51 @@
+//go:build linux
+
+package monitor
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// serviceState reads the state of a systemd unit with systemctl
+func serviceState(name string) (string, bool, error) {
+	out, err := exec.Command("systemctl", "show", "--property=LoadState,ActiveState,SubState", "--", name).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return "", false, fmt.Errorf("failed to query %s: %s", name, oneLine(exitErr.Stderr))
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to query %s: %w", name, err)
+	}
+
+	props := make(map[string]string)
+	for _, line := range strings.Split(string(out), "\n") {
+		if key, value, found := strings.Cut(strings.TrimSpace(line), "="); found {
+			props[key] = value
+		}
+	}
+
+	if props["LoadState"] == "not-found" {
+		return "not-found", false, fmt.Errorf("unit %s not found", name)
+	}
+
+	state := props["ActiveState"]
+	if sub := props["SubState"]; sub != "" {
+		state += " (" + sub + ")"
+	}
+	return state, props["ActiveState"] == "active", nil
+}
+
+// restartService restarts a systemd unit with systemctl
+func restartService(name string) error {
+	out, err := exec.Command("systemctl", "restart", "--", name).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to restart %s: %w: %s", name, err, oneLine(out))
+	}
+	return nil
+}
+
+// oneLine joins command output into a single line for reports and messages
+func oneLine(out []byte) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(strings.TrimSpace(string(out)), "\n", "; ")), " ")
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !windows
+
+package monitor
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// serviceState is not supported on this platform
+func serviceState(name string) (string, bool, error) {
+	return "", false, fmt.Errorf("service monitoring is not supported on %s", runtime.GOOS)
+}
+
+// restartService is not supported on this platform
+func restartService(name string) error {
+	return fmt.Errorf("service control is not supported on %s", runtime.GOOS)
+}
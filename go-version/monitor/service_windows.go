@@ -0,0 +1,45 @@
+//go:build windows
+
+package monitor
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// serviceState reads the state of a Windows service with sc.exe
+func serviceState(name string) (string, bool, error) {
+	out, err := exec.Command("sc", "query", name).Output()
+	if err != nil {
+		// sc exits with 1060 for services that do not exist
+		return "not-found", false, fmt.Errorf("failed to query %s: %w", name, err)
+	}
+
+	// The state line looks like "        STATE              : 4  RUNNING"
+	for _, line := range strings.Split(string(out), "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found || strings.TrimSpace(key) != "STATE" {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) < 2 {
+			break
+		}
+		state := fields[1]
+		return state, state == "RUNNING", nil
+	}
+
+	return "", false, fmt.Errorf("failed to parse state of %s", name)
+}
+
+// restartService restarts a Windows service with PowerShell, which stops
+// dependent services and waits for the service to start
+func restartService(name string) error {
+	script := fmt.Sprintf("Restart-Service -Name '%s' -Force -ErrorAction Stop", strings.ReplaceAll(name, "'", "''"))
+	out, err := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", script).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("failed to restart %s: %w: %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
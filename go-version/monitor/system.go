@@ -2,69 +2,51 @@ package monitor
 
 import (
 	"fmt"
-	"sort"
 
-	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
 	"github.com/shirou/gopsutil/v3/mem"
-	"github.com/shirou/gopsutil/v3/process"
 )
 
 // CPUInfo contains CPU information
 type CPUInfo struct {
-	Count   int
-	Percent float64
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
 }
 
 // MemoryInfo contains memory information
 type MemoryInfo struct {
-	Total       uint64
-	Used        uint64
-	Available   uint64
-	Percent     float64
+	Total     uint64  `json:"total"`
+	Used      uint64  `json:"used"`
+	Available uint64  `json:"available"`
+	Percent   float64 `json:"percent"`
 }
 
 // DiskInfo contains disk information
 type DiskInfo struct {
-	Device      string
-	Mountpoint  string
-	FSType      string
-	Total       uint64
-	Used        uint64
-	Free        uint64
-	Percent     float64
+	Device     string  `json:"device"`
+	Mountpoint string  `json:"mountpoint"`
+	FSType     string  `json:"fstype"`
+	Total      uint64  `json:"total"`
+	Used       uint64  `json:"used"`
+	Free       uint64  `json:"free"`
+	Percent    float64 `json:"percent"`
 }
 
 // ProcessInfo contains process information
 type ProcessInfo struct {
-	PID         int32
-	Name        string
-	CPUPercent  float64
-	MemoryMB    float64
-	MemoryPercent float32
+	PID           int32   `json:"pid"`
+	Name          string  `json:"name"`
+	CPUPercent    float64 `json:"cpu_percent"`
+	MemoryMB      float64 `json:"memory_mb"`
+	MemoryPercent float32 `json:"memory_percent"`
 }
 
-// GetCPUInfo retrieves CPU information
+// GetCPUInfo retrieves CPU information, measuring utilization over a short
+// window of its own. Callers that sample periodically should keep a
+// CPUSampler instead.
 func GetCPUInfo() (*CPUInfo, error) {
-	count, err := cpu.Counts(true)
-	if err != nil {
-		return nil, err
-	}
-
-	percent, err := cpu.Percent(0, false)
-	if err != nil {
-		return nil, err
-	}
-
-	cpuPercent := 0.0
-	if len(percent) > 0 {
-		cpuPercent = percent[0]
-	}
-
-	return &CPUInfo{
-		Count:   count,
-		Percent: cpuPercent,
-	}, nil
+	return NewCPUSampler().Info()
 }
 
 // GetMemoryInfo retrieves memory information
@@ -113,48 +95,12 @@ func GetDiskInfo() ([]*DiskInfo, error) {
 
 // GetTopProcessesByCPU returns top N processes by CPU usage
 func GetTopProcessesByCPU(n int) ([]*ProcessInfo, error) {
-	processes, err := process.Processes()
+	procInfos, err := ListProcesses()
 	if err != nil {
 		return nil, err
 	}
 
-	var procInfos []*ProcessInfo
-	for _, p := range processes {
-		name, err := p.Name()
-		if err != nil {
-			continue
-		}
-
-		cpuPercent, err := p.CPUPercent()
-		if err != nil {
-			cpuPercent = 0
-		}
-
-		memInfo, err := p.MemoryInfo()
-		memoryMB := 0.0
-		if err == nil && memInfo != nil {
-			memoryMB = float64(memInfo.RSS) / 1024 / 1024
-		}
-
-		memPercent, err := p.MemoryPercent()
-		if err != nil {
-			memPercent = 0
-		}
-
-		procInfos = append(procInfos, &ProcessInfo{
-			PID:           p.Pid,
-			Name:          name,
-			CPUPercent:    cpuPercent,
-			MemoryMB:      memoryMB,
-			MemoryPercent: memPercent,
-		})
-	}
-
-	// Sort by CPU percent
-	sort.Slice(procInfos, func(i, j int) bool {
-		return procInfos[i].CPUPercent > procInfos[j].CPUPercent
-	})
-
+	SortProcesses(procInfos, SortByCPU)
 	if len(procInfos) > n {
 		procInfos = procInfos[:n]
 	}
@@ -164,48 +110,12 @@ func GetTopProcessesByCPU(n int) ([]*ProcessInfo, error) {
 
 // GetTopProcessesByMemory returns top N processes by memory usage
 func GetTopProcessesByMemory(n int) ([]*ProcessInfo, error) {
-	processes, err := process.Processes()
+	procInfos, err := ListProcesses()
 	if err != nil {
 		return nil, err
 	}
 
-	var procInfos []*ProcessInfo
-	for _, p := range processes {
-		name, err := p.Name()
-		if err != nil {
-			continue
-		}
-
-		memPercent, err := p.MemoryPercent()
-		if err != nil {
-			continue
-		}
-
-		memInfo, err := p.MemoryInfo()
-		memoryMB := 0.0
-		if err == nil && memInfo != nil {
-			memoryMB = float64(memInfo.RSS) / 1024 / 1024
-		}
-
-		cpuPercent, err := p.CPUPercent()
-		if err != nil {
-			cpuPercent = 0
-		}
-
-		procInfos = append(procInfos, &ProcessInfo{
-			PID:           p.Pid,
-			Name:          name,
-			CPUPercent:    cpuPercent,
-			MemoryMB:      memoryMB,
-			MemoryPercent: memPercent,
-		})
-	}
-
-	// Sort by memory percent
-	sort.Slice(procInfos, func(i, j int) bool {
-		return procInfos[i].MemoryPercent > procInfos[j].MemoryPercent
-	})
-
+	SortProcesses(procInfos, SortByMemory)
 	if len(procInfos) > n {
 		procInfos = procInfos[:n]
 	}
@@ -215,17 +125,57 @@ func GetTopProcessesByMemory(n int) ([]*ProcessInfo, error) {
 
 // FormatBytes formats bytes into human-readable string
 func FormatBytes(bytes uint64) string {
+	return FormatBytesUnits(bytes, []string{"Б", "КБ", "МБ", "ГБ", "ТБ", "ПБ"})
+}
+
+// FormatBytesUnits formats bytes using the given unit names, starting
+// from bytes and growing by a factor of 1024
+func FormatBytesUnits(bytes uint64, units []string) string {
 	const unit = 1024
 	if bytes < unit {
-		return fmt.Sprintf("%.2f Б", float64(bytes))
+		return fmt.Sprintf("%.2f %s", float64(bytes), units[0])
 	}
 
 	div, exp := uint64(unit), 0
-	for n := bytes / unit; n >= unit; n /= unit {
+	for n := bytes / unit; n >= unit && exp < len(units)-2; n /= unit {
 		div *= unit
 		exp++
 	}
 
-	units := []string{"КБ", "МБ", "ГБ", "ТБ", "ПБ"}
-	return fmt.Sprintf("%.2f %s", float64(bytes)/float64(div), units[exp])
+	return fmt.Sprintf("%.2f %s", float64(bytes)/float64(div), units[exp+1])
+}
+
+// Summary is a short overview of the current system load
+type Summary struct {
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+	Disk   float64 `json:"disk"`
+	Uptime uint64  `json:"uptime"`
+}
+
+// GetSummary collects CPU and memory load, the fullest disk and uptime
+func GetSummary() *Summary {
+	summary := &Summary{}
+
+	if cpuInfo, err := GetCPUInfo(); err == nil {
+		summary.CPU = cpuInfo.Percent
+	}
+
+	if memInfo, err := GetMemoryInfo(); err == nil {
+		summary.Memory = memInfo.Percent
+	}
+
+	if disks, err := GetDiskInfo(); err == nil {
+		for _, d := range disks {
+			if d.Percent > summary.Disk {
+				summary.Disk = d.Percent
+			}
+		}
+	}
+
+	if uptime, err := host.Uptime(); err == nil {
+		summary.Uptime = uptime
+	}
+
+	return summary
 }
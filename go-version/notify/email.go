@@ -0,0 +1,147 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"system-monitor/config"
+	"time"
+)
+
+// smtpTimeout bounds a whole SMTP session
+const smtpTimeout = 30 * time.Second
+
+// Email sends notifications as plain text mail over SMTP
+type Email struct {
+	cfg      config.Email
+	computer string
+}
+
+// NewEmail creates a notifier for the SMTP settings; computer is added to
+// the subject
+func NewEmail(cfg config.Email, computer string) *Email {
+	return &Email{cfg: cfg, computer: computer}
+}
+
+// Notify sends the message to all recipients
+func (e *Email) Notify(ctx context.Context, msg Message) error {
+	body, err := e.compose(msg)
+	if err != nil {
+		return err
+	}
+
+	if err := e.send(ctx, body); err != nil {
+		// 5xx replies are permanent: bad recipient, rejected sender and so on
+		var smtpErr *textproto.Error
+		if errors.As(err, &smtpErr) && smtpErr.Code >= 500 {
+			return permanent("failed to send email: %w", err)
+		}
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// compose builds the mail headers and quoted-printable body
+func (e *Email) compose(msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	subject := fmt.Sprintf("[%s] %s", e.computer, subject(msg.Text))
+
+	fmt.Fprintf(&buf, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(e.cfg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", msg.Created.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	writer := quotedprintable.NewWriter(&buf)
+	text := strings.ReplaceAll(PlainText(msg.Text), "\n", "\r\n")
+	if _, err := writer.Write([]byte(text)); err != nil {
+		return nil, fmt.Errorf("failed to encode email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode email: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// send delivers a composed mail in one SMTP session
+func (e *Email) send(ctx context.Context, body []byte) error {
+	port := e.cfg.Port
+	if port == 0 {
+		port = 587
+		if e.cfg.TLS {
+			port = 465
+		}
+	}
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: e.cfg.Host}
+
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	var conn net.Conn
+	var err error
+	if e.cfg.TLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !e.cfg.TLS {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+
+	if e.cfg.Username != "" {
+		auth := smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(e.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range e.cfg.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(body); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,44 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// File appends notifications as plain text to a local file
+type File struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFile creates a notifier appending to path
+func NewFile(path string) *File {
+	return &File{path: path}
+}
+
+// Notify appends the message with a header line of its time, kind and
+// severity, followed by a blank line
+func (f *File) Notify(ctx context.Context, msg Message) error {
+	header := msg.Kind
+	if msg.Severity != "" {
+		header += "/" + msg.Severity
+	}
+	entry := fmt.Sprintf("%s [%s]\n%s\n\n", msg.Created.Format("2006-01-02 15:04:05"), header, PlainText(msg.Text))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(entry); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"system-monitor/config"
+)
+
+// Matrix posts notifications to a Matrix room through the client-server API
+type Matrix struct {
+	cfg  config.Matrix
+	http *http.Client
+}
+
+// NewMatrix creates a notifier for a room
+func NewMatrix(cfg config.Matrix) *Matrix {
+	return &Matrix{cfg: cfg, http: &http.Client{Timeout: httpTimeout}}
+}
+
+// Notify sends the message as an m.notice with an HTML body
+func (m *Matrix) Notify(ctx context.Context, msg Message) error {
+	// The transaction ID makes a retried request idempotent, so it comes
+	// from the message; one is made up only for messages sent directly
+	txn := msg.IdempotencyKey
+	if txn == "" {
+		b := make([]byte, 8)
+		rand.Read(b)
+		txn = hex.EncodeToString(b)
+	}
+
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimRight(m.cfg.Homeserver, "/"), url.PathEscape(m.cfg.RoomID), url.PathEscape(txn))
+
+	header := http.Header{"Authorization": {"Bearer " + m.cfg.AccessToken}}
+	return postJSON(ctx, m.http, http.MethodPut, endpoint, header, map[string]string{
+		"msgtype":        "m.notice",
+		"body":           PlainText(msg.Text),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": matrixHTML(msg.Text),
+	})
+}
@@ -0,0 +1,114 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"system-monitor/config"
+	"system-monitor/telegram"
+	"time"
+)
+
+// Message is a notification: a report, an alert or a status change. Text
+// is HTML as understood by Telegram; other channels convert it.
+type Message struct {
+	Kind     string `json:"kind"`
+	Severity string `json:"severity,omitempty"`
+	// ChatID sends the message to this Telegram chat through the built-in
+	// telegram channel, bypassing routing
+	ChatID  string    `json:"chat_id,omitempty"`
+	Text    string    `json:"text"`
+	Created time.Time `json:"created"`
+	// IdempotencyKey stays the same across delivery attempts of a message,
+	// so channels that support it can drop duplicates of a retried request
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+	// SentParts is how many parts of a message split by Telegram an earlier
+	// attempt delivered; a retry resumes after them
+	SentParts int `json:"sent_parts,omitempty"`
+}
+
+// Notifier delivers notifications to one destination
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+// Channels creates the notifiers configured in cfg, keyed by channel name,
+// including the built-in telegram channel for chat_id
+func Channels(cfg *config.Config, client *telegram.Client) map[string]Notifier {
+	channels := map[string]Notifier{
+		config.DefaultChannel: NewTelegram(client, cfg.ChatID),
+	}
+
+	for _, ch := range cfg.Channels {
+		switch ch.Type {
+		case config.ChannelTelegram:
+			channels[ch.Name] = NewTelegram(client, ch.ChatID)
+		case config.ChannelEmail:
+			channels[ch.Name] = NewEmail(ch.Email, cfg.ComputerName)
+		case config.ChannelWebhook:
+			channels[ch.Name] = NewWebhook(ch.URL, cfg.ComputerID, cfg.ComputerName)
+		case config.ChannelSlack:
+			channels[ch.Name] = NewSlack(ch.URL)
+		case config.ChannelMatrix:
+			channels[ch.Name] = NewMatrix(ch.Matrix)
+		case config.ChannelFile:
+			channels[ch.Name] = NewFile(ch.Path)
+		case config.ChannelSyslog:
+			channels[ch.Name] = NewSyslog(ch.Tag)
+		}
+	}
+
+	return channels
+}
+
+// Route returns the names of the channels a message is sent to
+func Route(routes []config.Route, msg Message) []string {
+	if msg.ChatID != "" {
+		return []string{config.DefaultChannel}
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, route := range routes {
+		if !route.Matches(msg.Kind, msg.Severity) {
+			continue
+		}
+		for _, name := range route.Channels {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+
+	if len(names) == 0 {
+		return []string{config.DefaultChannel}
+	}
+	return names
+}
+
+// permanentError is a delivery failure that retrying will not fix, such
+// as a rejected recipient or a missing webhook
+type permanentError struct {
+	err error
+}
+
+// Error implements error
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Retryable reports that the delivery should not be repeated
+func (e *permanentError) Retryable() bool {
+	return false
+}
+
+// permanent marks an error as not worth retrying
+func permanent(format string, args ...interface{}) error {
+	return &permanentError{err: fmt.Errorf(format, args...)}
+}
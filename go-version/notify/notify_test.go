@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"system-monitor/config"
+	"testing"
+)
+
+func TestRoute(t *testing.T) {
+	routes := []config.Route{
+		{Kind: config.KindReport, Channels: []string{"telegram"}},
+		{Kind: config.KindAlert, Severity: config.SeverityCritical, Channels: []string{"email", "telegram"}},
+		{Kind: config.KindAlert, Channels: []string{"telegram", "slack"}},
+	}
+
+	tests := []struct {
+		name string
+		msg  Message
+		want []string
+	}{
+		{"report", Message{Kind: config.KindReport}, []string{"telegram"}},
+		{"critical alert", Message{Kind: config.KindAlert, Severity: config.SeverityCritical}, []string{"email", "telegram", "slack"}},
+		{"warning", Message{Kind: config.KindAlert, Severity: config.SeverityWarning}, []string{"telegram", "slack"}},
+		{"unrouted", Message{Kind: config.KindStatus}, []string{config.DefaultChannel}},
+		{"reply to a chat", Message{Kind: config.KindAlert, ChatID: "42"}, []string{config.DefaultChannel}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Route(routes, tt.msg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Route() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostJSON(t *testing.T) {
+	tests := []struct {
+		status    int
+		wantErr   bool
+		retryable bool
+	}{
+		{http.StatusOK, false, false},
+		{http.StatusNoContent, false, false},
+		{http.StatusBadRequest, true, false},
+		{http.StatusNotFound, true, false},
+		{http.StatusTooManyRequests, true, true},
+		{http.StatusRequestTimeout, true, true},
+		{http.StatusBadGateway, true, true},
+	}
+
+	for _, tt := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+		}))
+		err := NewSlack(server.URL).Notify(context.Background(), Message{Text: "hi"})
+		server.Close()
+
+		if (err != nil) != tt.wantErr {
+			t.Errorf("status %d: error %v, want error %v", tt.status, err, tt.wantErr)
+			continue
+		}
+		if err == nil {
+			continue
+		}
+		var r interface{ Retryable() bool }
+		if retryable := !errors.As(err, &r) || r.Retryable(); retryable != tt.retryable {
+			t.Errorf("status %d: retryable %v, want %v", tt.status, retryable, tt.retryable)
+		}
+	}
+}
+
+func TestMatrixTransactionID(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{"event_id": "$1"}`))
+	}))
+	defer server.Close()
+
+	m := NewMatrix(config.Matrix{Homeserver: server.URL + "/", RoomID: "!room:example.org", AccessToken: "token"})
+	ctx := context.Background()
+
+	// A retried message keeps its transaction ID, so the homeserver drops
+	// the duplicate; other messages get their own
+	msg := Message{Text: "<b>disk</b>", IdempotencyKey: "key-1"}
+	for i := 0; i < 2; i++ {
+		if err := m.Notify(ctx, msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Notify(ctx, Message{Text: "direct"}); err != nil {
+		t.Fatal(err)
+	}
+
+	const prefix = "PUT /_matrix/client/v3/rooms/!room:example.org/send/m.room.message/"
+	if paths[0] != prefix+"key-1" || paths[1] != paths[0] {
+		t.Errorf("retried message sent to %q and %q, want %q twice", paths[0], paths[1], prefix+"key-1")
+	}
+	if paths[2] == paths[0] || len(paths[2]) <= len(prefix) {
+		t.Errorf("message without a key sent to %q", paths[2])
+	}
+}
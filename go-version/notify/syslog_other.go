@@ -0,0 +1,20 @@
+//go:build windows || plan9
+
+package notify
+
+import (
+	"context"
+)
+
+// Syslog is not available on this platform
+type Syslog struct{}
+
+// NewSyslog creates a notifier that always fails
+func NewSyslog(tag string) *Syslog {
+	return &Syslog{}
+}
+
+// Notify reports that syslog is not supported
+func (s *Syslog) Notify(ctx context.Context, msg Message) error {
+	return permanent("syslog is not supported on this platform")
+}
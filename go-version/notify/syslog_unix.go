@@ -0,0 +1,71 @@
+//go:build !windows && !plan9
+
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+	"strings"
+	"sync"
+	"system-monitor/config"
+)
+
+// defaultSyslogTag is the tag used when a syslog channel sets none
+const defaultSyslogTag = "system-monitor"
+
+// Syslog writes notifications to the local syslog daemon
+type Syslog struct {
+	tag    string
+	writer *syslog.Writer
+	mu     sync.Mutex
+}
+
+// NewSyslog creates a notifier with a syslog tag; the connection is opened
+// on first use
+func NewSyslog(tag string) *Syslog {
+	if tag == "" {
+		tag = defaultSyslogTag
+	}
+	return &Syslog{tag: tag}
+}
+
+// Notify logs the message on one line, with a priority following its severity
+func (s *Syslog) Notify(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.writer == nil {
+		writer, err := syslog.New(syslog.LOG_DAEMON|syslog.LOG_INFO, s.tag)
+		if err != nil {
+			return fmt.Errorf("failed to connect to syslog: %w", err)
+		}
+		s.writer = writer
+	}
+
+	var lines []string
+	for _, line := range strings.Split(PlainText(msg.Text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	text := strings.Join(lines, " | ")
+
+	var err error
+	switch {
+	case msg.Severity == config.SeverityCritical:
+		err = s.writer.Crit(text)
+	case msg.Kind == config.KindAlert || msg.Kind == config.KindStatus:
+		err = s.writer.Warning(text)
+	default:
+		err = s.writer.Info(text)
+	}
+	if err != nil {
+		// Reconnect on the next attempt
+		s.writer.Close()
+		s.writer = nil
+		return fmt.Errorf("failed to write to syslog: %w", err)
+	}
+
+	return nil
+}
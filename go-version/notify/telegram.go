@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+	"system-monitor/telegram"
+)
+
+// Telegram sends notifications to a Telegram chat
+type Telegram struct {
+	client *telegram.Client
+	chatID string
+}
+
+// NewTelegram creates a notifier for a chat
+func NewTelegram(client *telegram.Client, chatID string) *Telegram {
+	return &Telegram{client: client, chatID: chatID}
+}
+
+// Notify sends the message to the chat, or to msg.ChatID when set,
+// skipping the parts delivered by an earlier attempt
+func (t *Telegram) Notify(ctx context.Context, msg Message) error {
+	chatID := t.chatID
+	if msg.ChatID != "" {
+		chatID = msg.ChatID
+	}
+	return t.client.SendMessageFrom(ctx, chatID, msg.Text, msg.SentParts)
+}
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// htmlTag matches any HTML tag
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// slackTags maps Telegram HTML tags to Slack mrkdwn
+var slackTags = strings.NewReplacer(
+	"<b>", "*", "</b>", "*",
+	"<strong>", "*", "</strong>", "*",
+	"<i>", "_", "</i>", "_",
+	"<em>", "_", "</em>", "_",
+	"<s>", "~", "</s>", "~",
+	"<code>", "`", "</code>", "`",
+	"<pre>", "```", "</pre>", "```",
+)
+
+// PlainText converts a Telegram HTML message to plain text
+func PlainText(text string) string {
+	return html.UnescapeString(htmlTag.ReplaceAllString(text, ""))
+}
+
+// slackText converts a Telegram HTML message to Slack mrkdwn. Slack
+// expects &, < and > escaped just like Telegram, so entities are kept.
+func slackText(text string) string {
+	return htmlTag.ReplaceAllString(slackTags.Replace(text), "")
+}
+
+// matrixHTML converts a Telegram HTML message to the HTML body of a
+// Matrix event, where line breaks must be explicit
+func matrixHTML(text string) string {
+	// Line breaks inside <pre> are kept as they are
+	var b strings.Builder
+	inPre := false
+	for i, part := range strings.Split(text, "\n") {
+		if i > 0 {
+			if inPre {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("<br>")
+			}
+		}
+		b.WriteString(part)
+		if strings.Contains(part, "<pre>") {
+			inPre = true
+		}
+		if strings.Contains(part, "</pre>") {
+			inPre = false
+		}
+	}
+	return b.String()
+}
+
+// subject returns the first non-empty line of a message as plain text
+func subject(text string) string {
+	for _, line := range strings.Split(PlainText(text), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
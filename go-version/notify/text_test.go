@@ -0,0 +1,34 @@
+package notify
+
+import (
+	"testing"
+)
+
+func TestTextConversion(t *testing.T) {
+	const text = "🚨 <b>CPU</b> on <i>db &amp; co</i>\nload <code>95%</code>\n<pre>a\nb</pre>"
+
+	tests := []struct {
+		name    string
+		convert func(string) string
+		want    string
+	}{
+		{"plain", PlainText, "🚨 CPU on db & co\nload 95%\na\nb"},
+		{"slack", slackText, "🚨 *CPU* on _db &amp; co_\nload `95%`\n```a\nb```"},
+		{"matrix", matrixHTML, "🚨 <b>CPU</b> on <i>db &amp; co</i><br>load <code>95%</code><br><pre>a\nb</pre>"},
+		{"subject", subject, "🚨 CPU on db & co"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.convert(text); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubjectSkipsBlankLines(t *testing.T) {
+	if got := subject("\n  \n<b>Report</b>\nbody"); got != "Report" {
+		t.Errorf("subject() = %q, want %q", got, "Report")
+	}
+}
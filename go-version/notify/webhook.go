@@ -0,0 +1,107 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// httpTimeout bounds a single webhook request
+const httpTimeout = 30 * time.Second
+
+// Webhook posts notifications as JSON to an HTTP endpoint
+type Webhook struct {
+	url          string
+	computerID   string
+	computerName string
+	http         *http.Client
+}
+
+// webhookPayload is the JSON body posted by a webhook channel
+type webhookPayload struct {
+	Kind         string    `json:"kind"`
+	Severity     string    `json:"severity,omitempty"`
+	ComputerID   string    `json:"computer_id"`
+	ComputerName string    `json:"computer_name"`
+	Text         string    `json:"text"`
+	HTML         string    `json:"html"`
+	Created      time.Time `json:"created"`
+}
+
+// NewWebhook creates a notifier posting to url on behalf of a computer
+func NewWebhook(url, computerID, computerName string) *Webhook {
+	return &Webhook{
+		url:          url,
+		computerID:   computerID,
+		computerName: computerName,
+		http:         &http.Client{Timeout: httpTimeout},
+	}
+}
+
+// Notify posts the message with its kind, severity and computer
+func (w *Webhook) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, w.http, http.MethodPost, w.url, nil, webhookPayload{
+		Kind:         msg.Kind,
+		Severity:     msg.Severity,
+		ComputerID:   w.computerID,
+		ComputerName: w.computerName,
+		Text:         PlainText(msg.Text),
+		HTML:         msg.Text,
+		Created:      msg.Created,
+	})
+}
+
+// Slack posts notifications to a Slack-compatible incoming webhook
+type Slack struct {
+	url  string
+	http *http.Client
+}
+
+// NewSlack creates a notifier for an incoming webhook URL
+func NewSlack(url string) *Slack {
+	return &Slack{url: url, http: &http.Client{Timeout: httpTimeout}}
+}
+
+// Notify posts the message as mrkdwn text
+func (s *Slack) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, s.http, http.MethodPost, s.url, nil, map[string]string{"text": slackText(msg.Text)})
+}
+
+// postJSON sends a JSON payload and treats any 2xx response as success.
+// Client errors other than 408 and 429 are permanent.
+func postJSON(ctx context.Context, client *http.Client, method, url string, header http.Header, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+		resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+		return permanent("request rejected: %s %s", resp.Status, bytes.TrimSpace(detail))
+	}
+	return fmt.Errorf("request failed: %s %s", resp.Status, bytes.TrimSpace(detail))
+}
@@ -0,0 +1,377 @@
+package outbox
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"system-monitor/notify"
+	"time"
+)
+
+const (
+	// maxMessages bounds the queue; the oldest messages are dropped first
+	maxMessages = 500
+	// minRetry is the pause after the first failed delivery
+	minRetry = 10 * time.Second
+	// maxRetry caps the pause between delivery attempts
+	maxRetry = 5 * time.Minute
+	// delayedAfter is how late a message may be delivered before it is
+	// marked as delayed
+	delayedAfter = time.Minute
+	// deliveryTimeout bounds a single delivery attempt
+	deliveryTimeout = 2 * time.Minute
+)
+
+// Entry is a message queued for one channel
+type Entry struct {
+	ID      uint64 `json:"id"`
+	Channel string `json:"channel,omitempty"`
+	notify.Message
+}
+
+// backoff tracks failed deliveries to a channel
+type backoff struct {
+	until time.Time
+	retry time.Duration
+}
+
+// Outbox is a persistent queue of outgoing notifications. It implements
+// notify.Notifier: messages are routed to channels, written to disk and
+// delivered by Run in order per channel, so that reports and alerts
+// survive network outages and restarts.
+type Outbox struct {
+	channels map[string]notify.Notifier
+	routes   []config.Route
+	chatID   string
+	language string
+	path     string
+	entries  []Entry
+	nextID   uint64
+	wake     chan struct{}
+	mu       sync.Mutex
+}
+
+// Open loads the queue saved next to the log file. On a read error the
+// returned outbox is empty but usable.
+func Open(cfg *config.Config, channels map[string]notify.Notifier) (*Outbox, error) {
+	o := &Outbox{
+		channels: channels,
+		routes:   cfg.Routes,
+		chatID:   cfg.ChatID,
+		language: cfg.Language,
+		path:     cfg.DataPath("outbox.json"),
+		nextID:   1,
+		wake:     make(chan struct{}, 1),
+	}
+
+	data, err := os.ReadFile(o.path)
+	if os.IsNotExist(err) {
+		return o, nil
+	}
+	if err != nil {
+		return o, fmt.Errorf("failed to read outbox: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &o.entries); err != nil {
+		o.entries = nil
+		return o, fmt.Errorf("failed to parse outbox: %w", err)
+	}
+
+	for i, entry := range o.entries {
+		if entry.Channel == "" {
+			o.entries[i].Channel = config.DefaultChannel
+		}
+		if entry.IdempotencyKey == "" {
+			o.entries[i].IdempotencyKey = newKey()
+		}
+		if entry.ID >= o.nextID {
+			o.nextID = entry.ID + 1
+		}
+	}
+
+	if len(o.entries) > 0 {
+		log.Printf("В очереди %d неотправленных сообщений", len(o.entries))
+	}
+
+	return o, nil
+}
+
+// Notify queues a message for every channel its kind and severity are
+// routed to. The message stays queued even if saving the queue fails.
+func (o *Outbox) Notify(ctx context.Context, msg notify.Message) error {
+	if msg.Created.IsZero() {
+		msg.Created = time.Now()
+	}
+
+	o.mu.Lock()
+	for _, channel := range notify.Route(o.routes, msg) {
+		entry := Entry{ID: o.nextID, Channel: channel, Message: msg}
+		entry.IdempotencyKey = newKey()
+		o.entries = append(o.entries, entry)
+		o.nextID++
+	}
+
+	if dropped := len(o.entries) - maxMessages; dropped > 0 {
+		log.Printf("Очередь сообщений переполнена, удалено старых сообщений: %d", dropped)
+		o.entries = append([]Entry(nil), o.entries[dropped:]...)
+	}
+
+	err := o.saveLocked()
+	o.mu.Unlock()
+
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+
+	return err
+}
+
+// Len returns the number of messages waiting to be delivered
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.entries)
+}
+
+// Run delivers queued messages until ctx is done. Within a channel
+// messages are sent in order: one that cannot be delivered holds back the
+// ones after it and is retried with a growing pause, while other channels
+// carry on. Messages a channel rejects outright are dropped.
+func (o *Outbox) Run(ctx context.Context) {
+	backoffs := make(map[string]*backoff)
+
+	for {
+		// wait is the shortest backoff left; progress is set when a message
+		// left the queue, so the next ones are tried right away
+		var wait time.Duration
+		progress := false
+		for _, entry := range o.heads() {
+			b := backoffs[entry.Channel]
+			if b != nil && time.Now().Before(b.until) {
+				if left := time.Until(b.until); wait == 0 || left < wait {
+					wait = left
+				}
+				continue
+			}
+
+			err := o.send(ctx, entry)
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				delete(backoffs, entry.Channel)
+				progress = true
+				continue
+			}
+
+			if b == nil {
+				b = &backoff{retry: minRetry}
+				backoffs[entry.Channel] = b
+			} else {
+				b.retry = min(b.retry*2, maxRetry)
+			}
+			b.until = time.Now().Add(b.retry)
+			log.Printf("Ошибка отправки в канал %s (%d в очереди), повтор через %v: %v", entry.Channel, o.Len(), b.retry, err)
+			if wait == 0 || b.retry < wait {
+				wait = b.retry
+			}
+		}
+
+		if progress {
+			continue
+		}
+
+		// Sleep until a backoff ends or a message is queued
+		var timer *time.Timer
+		var timeout <-chan time.Time
+		if o.Len() > 0 && wait > 0 {
+			timer = time.NewTimer(wait)
+			timeout = timer.C
+		}
+
+		select {
+		case <-timeout:
+		case <-o.wake:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// Flush makes one more attempt to deliver everything queued, in order per
+// channel, until a channel fails or ctx is done. It is used on shutdown,
+// after Run has returned; what is left is delivered after the next start.
+func (o *Outbox) Flush(ctx context.Context) {
+	failed := make(map[string]bool)
+
+	for ctx.Err() == nil {
+		sent := false
+		for _, entry := range o.heads() {
+			if failed[entry.Channel] {
+				continue
+			}
+			if err := o.send(ctx, entry); err != nil {
+				failed[entry.Channel] = true
+				continue
+			}
+			sent = true
+		}
+		if !sent {
+			break
+		}
+	}
+
+	if left := o.Len(); left > 0 {
+		log.Printf("Не отправлено сообщений: %d, они будут отправлены после запуска", left)
+	}
+}
+
+// send delivers a message to its channel and removes it from the queue,
+// unless it failed for a reason that may pass, which is returned
+func (o *Outbox) send(ctx context.Context, entry Entry) error {
+	notifier, ok := o.channels[entry.Channel]
+	if !ok {
+		log.Printf("Канал %s не настроен, сообщение удалено из очереди", entry.Channel)
+		o.remove(entry.ID)
+		return nil
+	}
+
+	msg := o.mark(entry.Message)
+	err := o.deliver(ctx, notifier, msg)
+
+	// Resume a split message after the parts that went out; the text is
+	// kept as sent, so it splits the same way next time
+	var partial interface{ SentParts() int }
+	if errors.As(err, &partial) {
+		o.progress(entry.ID, msg.Text, partial.SentParts())
+	}
+
+	var retryable interface{ Retryable() bool }
+	if err != nil && !(errors.As(err, &retryable) && !retryable.Retryable()) {
+		return err
+	}
+
+	if err != nil {
+		log.Printf("Сообщение отклонено каналом %s и удалено из очереди: %v", entry.Channel, err)
+	}
+	o.remove(entry.ID)
+	return nil
+}
+
+// mark notes in a message that it is sent late, unless part of it has
+// already been sent
+func (o *Outbox) mark(msg notify.Message) notify.Message {
+	if msg.SentParts > 0 || time.Since(msg.Created) <= delayedAfter {
+		return msg
+	}
+
+	chatID := o.chatID
+	if msg.ChatID != "" {
+		chatID = msg.ChatID
+	}
+	loc := i18n.ForChat(chatID, o.language)
+	msg.Text = loc.T("outbox.delayed", loc.DateTime(msg.Created)) + "\n\n" + msg.Text
+	return msg
+}
+
+// deliver sends a message to a channel
+func (o *Outbox) deliver(ctx context.Context, notifier notify.Notifier, msg notify.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	return notifier.Notify(ctx, msg)
+}
+
+// progress records that the first sent parts of a queued message were
+// delivered as text
+func (o *Outbox) progress(id uint64, text string, sent int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := range o.entries {
+		if o.entries[i].ID == id {
+			o.entries[i].Text = text
+			o.entries[i].SentParts = sent
+			break
+		}
+	}
+
+	if err := o.saveLocked(); err != nil {
+		log.Printf("Ошибка сохранения очереди сообщений: %v", err)
+	}
+}
+
+// heads returns the oldest queued message of every channel
+func (o *Outbox) heads() []Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var heads []Entry
+	seen := make(map[string]bool)
+	for _, entry := range o.entries {
+		if !seen[entry.Channel] {
+			seen[entry.Channel] = true
+			heads = append(heads, entry)
+		}
+	}
+	return heads
+}
+
+// remove deletes a delivered message from the queue
+func (o *Outbox) remove(id uint64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i, entry := range o.entries {
+		if entry.ID == id {
+			o.entries = append(o.entries[:i], o.entries[i+1:]...)
+			break
+		}
+	}
+
+	if err := o.saveLocked(); err != nil {
+		log.Printf("Ошибка сохранения очереди сообщений: %v", err)
+	}
+}
+
+// newKey returns a random idempotency key. Entry IDs start over when the
+// queue is empty, so they cannot serve as keys.
+func newKey() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// saveLocked writes the queue to disk; o.mu must be held
+func (o *Outbox) saveLocked() error {
+	data, err := json.MarshalIndent(o.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal outbox: %w", err)
+	}
+
+	tmp := o.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write outbox: %w", err)
+	}
+
+	if err := os.Rename(tmp, o.path); err != nil {
+		return fmt.Errorf("failed to replace outbox: %w", err)
+	}
+
+	return nil
+}
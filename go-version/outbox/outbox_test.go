@@ -0,0 +1,253 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"system-monitor/config"
+	"system-monitor/notify"
+	"testing"
+	"time"
+)
+
+// rejected is a delivery error that is not worth retrying
+type rejected struct{}
+
+func (rejected) Error() string   { return "rejected" }
+func (rejected) Retryable() bool { return false }
+
+// channel is a notifier that records delivered texts and fails with the
+// queued errors first
+type channel struct {
+	errs []error
+	sent []string
+}
+
+func (c *channel) Notify(ctx context.Context, msg notify.Message) error {
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		if err != nil {
+			return err
+		}
+	}
+	c.sent = append(c.sent, msg.Text)
+	return nil
+}
+
+func TestFlush(t *testing.T) {
+	unavailable := errors.New("unavailable")
+
+	tests := []struct {
+		name string
+		// errs are returned by the first deliveries to each channel
+		errs map[string][]error
+		// want lists the delivered texts per channel
+		want map[string][]string
+		// left is the number of messages still queued
+		left int
+	}{
+		{
+			name: "in order",
+			want: map[string][]string{"telegram": {"one", "two", "three"}, "email": {"two", "three"}},
+		},
+		{
+			name: "failed channel holds back later messages",
+			errs: map[string][]error{"email": {unavailable}},
+			want: map[string][]string{"telegram": {"one", "two", "three"}},
+			left: 2,
+		},
+		{
+			name: "rejected message is dropped",
+			errs: map[string][]error{"telegram": {rejected{}}},
+			want: map[string][]string{"telegram": {"two", "three"}, "email": {"two", "three"}},
+		},
+		{
+			name: "wrapped rejection is dropped",
+			errs: map[string][]error{"email": {nil, errors.Join(errors.New("smtp"), rejected{})}},
+			want: map[string][]string{"telegram": {"one", "two", "three"}, "email": {"two"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channels := map[string]*channel{
+				"telegram": {errs: tt.errs["telegram"]},
+				"email":    {errs: tt.errs["email"]},
+			}
+			cfg := &config.Config{
+				LogFile: filepath.Join(t.TempDir(), "monitor.log"),
+				Routes: []config.Route{
+					{Kind: config.KindReport, Channels: []string{"telegram"}},
+					{Kind: config.KindAlert, Channels: []string{"telegram", "email"}},
+				},
+			}
+			o, err := Open(cfg, map[string]notify.Notifier{"telegram": channels["telegram"], "email": channels["email"]})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			o.Notify(ctx, notify.Message{Kind: config.KindReport, Text: "one"})
+			o.Notify(ctx, notify.Message{Kind: config.KindAlert, Text: "two"})
+			o.Notify(ctx, notify.Message{Kind: config.KindAlert, Text: "three"})
+			o.Flush(ctx)
+
+			for name, ch := range channels {
+				if !reflect.DeepEqual(ch.sent, tt.want[name]) {
+					t.Errorf("%s got %q, want %q", name, ch.sent, tt.want[name])
+				}
+			}
+			if left := o.Len(); left != tt.left {
+				t.Errorf("%d messages left, want %d", left, tt.left)
+			}
+		})
+	}
+}
+
+func TestQueueSurvivesRestart(t *testing.T) {
+	cfg := &config.Config{LogFile: filepath.Join(t.TempDir(), "monitor.log")}
+	failing := &channel{errs: []error{errors.New("unavailable")}}
+
+	o, err := Open(cfg, map[string]notify.Notifier{config.DefaultChannel: failing})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	o.Notify(ctx, notify.Message{Text: "one"})
+	o.Notify(ctx, notify.Message{Text: "two"})
+	o.Flush(ctx)
+	if o.Len() != 2 {
+		t.Fatalf("%d messages left after a failure, want 2", o.Len())
+	}
+
+	// The queue is reloaded and delivered in order
+	delivered := &channel{}
+	o, err = Open(cfg, map[string]notify.Notifier{config.DefaultChannel: delivered})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Flush(ctx)
+
+	if want := []string{"one", "two"}; !reflect.DeepEqual(delivered.sent, want) {
+		t.Errorf("delivered %q, want %q", delivered.sent, want)
+	}
+	if o.Len() != 0 {
+		t.Errorf("%d messages left, want 0", o.Len())
+	}
+}
+
+func TestUnknownChannelIsDropped(t *testing.T) {
+	cfg := &config.Config{
+		LogFile: filepath.Join(t.TempDir(), "monitor.log"),
+		Routes:  []config.Route{{Channels: []string{"missing"}}},
+	}
+	o, err := Open(cfg, map[string]notify.Notifier{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	o.Notify(ctx, notify.Message{Text: "one"})
+	o.Flush(ctx)
+
+	if o.Len() != 0 {
+		t.Errorf("%d messages left, want 0", o.Len())
+	}
+}
+
+// partial is a failure after the first parts of a message were sent
+type partial struct{ sent int }
+
+func (p partial) Error() string  { return "failed after some parts" }
+func (p partial) SentParts() int { return p.sent }
+
+// recorder is a notifier that keeps the messages it was asked to send and
+// fails with the queued errors first
+type recorder struct {
+	errs     []error
+	messages []notify.Message
+}
+
+func (r *recorder) Notify(ctx context.Context, msg notify.Message) error {
+	r.messages = append(r.messages, msg)
+	if len(r.messages) <= len(r.errs) {
+		return r.errs[len(r.messages)-1]
+	}
+	return nil
+}
+
+func TestPartialDeliveryResumes(t *testing.T) {
+	cfg := &config.Config{LogFile: filepath.Join(t.TempDir(), "monitor.log"), Language: "en"}
+	ch := &recorder{errs: []error{partial{sent: 2}}}
+
+	o, err := Open(cfg, map[string]notify.Notifier{config.DefaultChannel: ch})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Late enough to be marked as delayed
+	ctx := context.Background()
+	o.Notify(ctx, notify.Message{Text: "report", Created: time.Now().Add(-time.Hour)})
+	o.Flush(ctx)
+	if o.Len() != 1 {
+		t.Fatalf("%d messages left after a partial failure, want 1", o.Len())
+	}
+
+	// The progress survives a restart
+	o, err = Open(cfg, map[string]notify.Notifier{config.DefaultChannel: ch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Flush(ctx)
+	if o.Len() != 0 {
+		t.Fatalf("%d messages left, want 0", o.Len())
+	}
+
+	if len(ch.messages) != 2 {
+		t.Fatalf("%d attempts, want 2", len(ch.messages))
+	}
+	first, retry := ch.messages[0], ch.messages[1]
+	if first.SentParts != 0 || retry.SentParts != 2 {
+		t.Errorf("attempts skipped %d and %d parts, want 0 and 2", first.SentParts, retry.SentParts)
+	}
+	if !strings.HasSuffix(first.Text, "\n\nreport") || retry.Text != first.Text {
+		t.Errorf("retry text %q differs from the first attempt %q", retry.Text, first.Text)
+	}
+}
+
+func TestRetryKeepsIdempotencyKey(t *testing.T) {
+	cfg := &config.Config{LogFile: filepath.Join(t.TempDir(), "monitor.log")}
+	ch := &recorder{errs: []error{errors.New("unavailable")}}
+
+	o, err := Open(cfg, map[string]notify.Notifier{config.DefaultChannel: ch})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	o.Notify(ctx, notify.Message{Text: "one"})
+	o.Notify(ctx, notify.Message{Text: "two"})
+	o.Flush(ctx)
+
+	// The failed message is retried after a restart
+	o, err = Open(cfg, map[string]notify.Notifier{config.DefaultChannel: ch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.Flush(ctx)
+
+	if len(ch.messages) != 3 {
+		t.Fatalf("%d attempts, want 3", len(ch.messages))
+	}
+	first, retry, second := ch.messages[0], ch.messages[1], ch.messages[2]
+	if first.IdempotencyKey == "" || retry.IdempotencyKey != first.IdempotencyKey {
+		t.Errorf("retry key %q differs from the first attempt %q", retry.IdempotencyKey, first.IdempotencyKey)
+	}
+	if second.IdempotencyKey == first.IdempotencyKey {
+		t.Error("two messages share an idempotency key")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"system-monitor/report"
+	"system-monitor/storage"
+)
+
+// runPrint loads the configuration without requiring Telegram credentials
+// and prints a report to stdout. A missing config file is not an error.
+func runPrint(configPath, format string) error {
+	cfg, err := config.Load(configPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		cfg = config.Default()
+	} else if err != nil {
+		return err
+	}
+
+	i18n.SetLocation(cfg.Location())
+	renderer, err := report.NewRenderer(format, i18n.New(cfg.Language))
+	if err != nil {
+		return err
+	}
+
+	// Use history only if the agent has already recorded some
+	var store *storage.Store
+	if _, err := os.Stat(cfg.History.Dir); err == nil {
+		if store, err = storage.Open(cfg); err != nil {
+			log.Printf("Ошибка открытия истории метрик: %v", err)
+		}
+	}
+
+	return printReport(os.Stdout, renderer, report.Collect(cfg, store))
+}
+
+// printReport renders a report and writes it to w
+func printReport(w io.Writer, renderer report.Renderer, r *report.Report) error {
+	output, err := renderer.Render(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, output)
+	return err
+}
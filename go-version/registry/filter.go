@@ -0,0 +1,61 @@
+package registry
+
+import (
+	"strings"
+)
+
+// Filter selects computers by tag, group, owner or name
+type Filter struct {
+	Tag   string
+	Group string
+	Owner string
+	Name  string
+}
+
+// ParseFilter builds a filter from command arguments such as
+// "tag:office group:almaty owner:ivan"; any other word matches the name
+func ParseFilter(args []string) Filter {
+	var f Filter
+	for _, arg := range args {
+		key, value, found := strings.Cut(arg, ":")
+		if !found {
+			f.Name = strings.TrimSpace(f.Name + " " + arg)
+			continue
+		}
+
+		switch strings.ToLower(key) {
+		case "tag":
+			f.Tag = value
+		case "group":
+			f.Group = value
+		case "owner":
+			f.Owner = value
+		default:
+			f.Name = strings.TrimSpace(f.Name + " " + arg)
+		}
+	}
+	return f
+}
+
+// Empty reports whether the filter matches every computer
+func (f Filter) Empty() bool {
+	return f == Filter{}
+}
+
+// Matches reports whether a computer satisfies every set criterion
+func (f Filter) Matches(c Computer) bool {
+	if f.Tag != "" && !c.HasTag(f.Tag) {
+		return false
+	}
+	if f.Group != "" && !strings.EqualFold(f.Group, c.Group) {
+		return false
+	}
+	if f.Owner != "" && !strings.EqualFold(f.Owner, c.Owner) {
+		return false
+	}
+	if f.Name != "" && !strings.Contains(strings.ToLower(c.Name), strings.ToLower(f.Name)) &&
+		!strings.EqualFold(f.Name, c.ID) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,345 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"system-monitor/monitor"
+	"time"
+)
+
+// DefaultOfflineAfter is how long a computer may stay silent before it is
+// considered offline when no threshold is configured
+const DefaultOfflineAfter = 10 * time.Minute
+
+// Computer is a known monitored computer
+type Computer struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Tags     []string  `json:"tags,omitempty"`
+	Group    string    `json:"group,omitempty"`
+	Owner    string    `json:"owner,omitempty"`
+	LastSeen time.Time `json:"last_seen"`
+	// Renamed is set when the name was changed from the bot, so that
+	// heartbeats no longer overwrite it
+	Renamed bool `json:"renamed,omitempty"`
+	// Offline is set once the computer has been reported offline
+	Offline bool `json:"offline,omitempty"`
+	// Local is true for the computer this process runs on
+	Local bool `json:"-"`
+	// Summary is the load reported with the last heartbeat
+	Summary *monitor.Summary `json:"-"`
+
+	offlineAfter time.Duration
+}
+
+// Info is what a computer reports about itself with each heartbeat
+type Info struct {
+	ID      string
+	Name    string
+	Tags    []string
+	Group   string
+	Owner   string
+	Local   bool
+	Summary *monitor.Summary
+}
+
+// Online reports whether the computer has been seen recently
+func (c Computer) Online(now time.Time) bool {
+	return c.Local || now.Sub(c.LastSeen) <= c.offlineAfter
+}
+
+// HasTag reports whether the computer carries a tag
+func (c Computer) HasTag(tag string) bool {
+	for _, t := range c.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusHandler is called when a computer goes offline or comes back
+type StatusHandler func(comp Computer, online bool, silence time.Duration)
+
+// Registry keeps the set of known computers, persisted to a JSON file
+type Registry struct {
+	computers    map[string]*Computer
+	offlineAfter time.Duration
+	onStatus     StatusHandler
+	path         string
+	dirty        bool
+	mu           sync.RWMutex
+}
+
+// Open loads the registry from path (an empty path keeps it in memory
+// only). Computers are considered offline after offlineAfter without a
+// heartbeat.
+func Open(path string, offlineAfter time.Duration) (*Registry, error) {
+	if offlineAfter <= 0 {
+		offlineAfter = DefaultOfflineAfter
+	}
+
+	r := &Registry{
+		computers:    make(map[string]*Computer),
+		offlineAfter: offlineAfter,
+		path:         path,
+	}
+
+	if path == "" {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return r, fmt.Errorf("failed to read registry: %w", err)
+	}
+
+	var computers []*Computer
+	if err := json.Unmarshal(data, &computers); err != nil {
+		return r, fmt.Errorf("failed to parse registry: %w", err)
+	}
+
+	for _, comp := range computers {
+		r.computers[comp.ID] = comp
+	}
+
+	return r, nil
+}
+
+// SetStatusHandler sets the function notified about offline/online changes
+func (r *Registry) SetStatusHandler(handler StatusHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.onStatus = handler
+}
+
+// Touch records a heartbeat, registering the computer if needed
+func (r *Registry) Touch(info Info) {
+	r.mu.Lock()
+
+	comp, exists := r.computers[info.ID]
+	if !exists {
+		comp = &Computer{ID: info.ID}
+		r.computers[info.ID] = comp
+	}
+
+	now := time.Now()
+	silence := now.Sub(comp.LastSeen)
+	cameBack := comp.Offline
+
+	if !comp.Renamed {
+		comp.Name = info.Name
+	}
+	comp.Tags = info.Tags
+	comp.Group = info.Group
+	comp.Owner = info.Owner
+	comp.Local = info.Local
+	comp.LastSeen = now
+	comp.Offline = false
+	if info.Summary != nil {
+		comp.Summary = info.Summary
+	}
+
+	snapshot := r.snapshot(comp)
+	handler := r.onStatus
+	r.dirty = true
+	if !exists {
+		r.saveLocked()
+	}
+	r.mu.Unlock()
+
+	if cameBack && handler != nil {
+		handler(snapshot, true, silence)
+	}
+}
+
+// Rename sets a display name that heartbeats will not overwrite
+func (r *Registry) Rename(id, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comp, exists := r.computers[id]
+	if !exists {
+		return fmt.Errorf("unknown computer %q", id)
+	}
+
+	comp.Name = name
+	comp.Renamed = true
+	return r.saveLocked()
+}
+
+// Forget removes a computer; it reappears if it sends another heartbeat
+func (r *Registry) Forget(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	comp, exists := r.computers[id]
+	if !exists {
+		return fmt.Errorf("unknown computer %q", id)
+	}
+	if comp.Local {
+		return fmt.Errorf("cannot forget the local computer")
+	}
+
+	delete(r.computers, id)
+	return r.saveLocked()
+}
+
+// Get returns a copy of a computer by ID
+func (r *Registry) Get(id string) (Computer, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	comp, exists := r.computers[id]
+	if !exists {
+		return Computer{}, false
+	}
+	return r.snapshot(comp), true
+}
+
+// List returns copies of all computers sorted by name
+func (r *Registry) List() []Computer {
+	return r.Find(Filter{})
+}
+
+// Find returns copies of the computers matching a filter, sorted by name
+func (r *Registry) Find(filter Filter) []Computer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]Computer, 0, len(r.computers))
+	for _, comp := range r.computers {
+		if filter.Matches(*comp) {
+			result = append(result, r.snapshot(comp))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
+
+// Watch checks every interval for computers that stopped reporting,
+// notifies the status handler and saves pending changes until ctx is done
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.markOffline(time.Now())
+			r.Flush()
+		case <-ctx.Done():
+			r.Flush()
+			return
+		}
+	}
+}
+
+// markOffline flags computers silent for longer than the threshold
+func (r *Registry) markOffline(now time.Time) {
+	r.mu.Lock()
+	var silent []Computer
+	for _, comp := range r.computers {
+		if comp.Offline || comp.Local || now.Sub(comp.LastSeen) <= r.offlineAfter {
+			continue
+		}
+		comp.Offline = true
+		r.dirty = true
+		silent = append(silent, r.snapshot(comp))
+	}
+	handler := r.onStatus
+	r.mu.Unlock()
+
+	for _, comp := range silent {
+		log.Printf("Компьютер %s (%s) перестал отвечать", comp.Name, comp.ID)
+		if handler != nil {
+			handler(comp, false, now.Sub(comp.LastSeen))
+		}
+	}
+}
+
+// RunLocal keeps the local computer's entry fresh, sending a heartbeat
+// every interval and saving pending changes until ctx is done
+func (r *Registry) RunLocal(ctx context.Context, info Info, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	info.Local = true
+	for {
+		info.Summary = monitor.GetSummary()
+		r.Touch(info)
+		r.Flush()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Flush saves the registry if it changed since the last save
+func (r *Registry) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.dirty {
+		return
+	}
+	if err := r.saveLocked(); err != nil {
+		log.Printf("Ошибка сохранения списка компьютеров: %v", err)
+	}
+}
+
+// saveLocked writes the registry to disk; r.mu must be held
+func (r *Registry) saveLocked() error {
+	r.dirty = false
+	if r.path == "" {
+		return nil
+	}
+
+	computers := make([]*Computer, 0, len(r.computers))
+	for _, comp := range r.computers {
+		computers = append(computers, comp)
+	}
+	sort.Slice(computers, func(i, j int) bool {
+		return computers[i].ID < computers[j].ID
+	})
+
+	data, err := json.MarshalIndent(computers, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal registry: %w", err)
+	}
+
+	tmp := r.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write registry: %w", err)
+	}
+
+	if err := os.Rename(tmp, r.path); err != nil {
+		return fmt.Errorf("failed to replace registry: %w", err)
+	}
+
+	return nil
+}
+
+// snapshot copies a computer for use outside the lock; r.mu must be held
+func (r *Registry) snapshot(comp *Computer) Computer {
+	c := *comp
+	c.Tags = append([]string(nil), comp.Tags...)
+	c.offlineAfter = r.offlineAfter
+	return c
+}
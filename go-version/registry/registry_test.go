@@ -0,0 +1,142 @@
+package registry
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// status is one call of the status handler
+type status struct {
+	id     string
+	online bool
+}
+
+func TestOfflineNotices(t *testing.T) {
+	r, err := Open("", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notices []status
+	r.SetStatusHandler(func(comp Computer, online bool, silence time.Duration) {
+		notices = append(notices, status{comp.ID, online})
+	})
+
+	r.Touch(Info{ID: "agent", Name: "agent"})
+	r.Touch(Info{ID: "local", Name: "local", Local: true})
+
+	r.markOffline(time.Now())
+	if len(notices) != 0 {
+		t.Fatalf("notices %v before the threshold passed", notices)
+	}
+
+	later := time.Now().Add(time.Hour)
+	r.markOffline(later)
+	r.markOffline(later)
+	if comp, _ := r.Get("agent"); comp.Online(later) || !comp.Offline {
+		t.Errorf("agent is still online after an hour of silence")
+	}
+	if comp, _ := r.Get("local"); !comp.Online(later) {
+		t.Errorf("the local computer went offline")
+	}
+
+	r.Touch(Info{ID: "agent", Name: "agent"})
+	want := []status{{"agent", false}, {"agent", true}}
+	if len(notices) != len(want) || notices[0] != want[0] || notices[1] != want[1] {
+		t.Errorf("notices %v, want %v", notices, want)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "computers.json")
+	r, err := Open(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Touch(Info{ID: "pc-1", Name: "pc-1", Tags: []string{"office"}, Group: "almaty", Owner: "ivan"})
+	r.Touch(Info{ID: "pc-2", Name: "pc-2"})
+	r.Touch(Info{ID: "local", Name: "local", Local: true})
+	if err := r.Rename("pc-1", "Accounting"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Forget("pc-2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Forget("local"); err == nil {
+		t.Error("the local computer was forgotten")
+	}
+	if err := r.Rename("missing", "name"); err == nil {
+		t.Error("an unknown computer was renamed")
+	}
+	r.Flush()
+
+	r, err = Open(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, comp := range r.List() {
+		ids = append(ids, comp.ID)
+	}
+	if want := []string{"pc-1", "local"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("reopened registry has %q, want %q sorted by name", ids, want)
+	}
+
+	comp, _ := r.Get("pc-1")
+	if comp.Name != "Accounting" || comp.Group != "almaty" || comp.Owner != "ivan" || !comp.HasTag("office") {
+		t.Errorf("reopened computer %+v", comp)
+	}
+
+	// A heartbeat keeps the name given from the bot
+	r.Touch(Info{ID: "pc-1", Name: "pc-1"})
+	if comp, _ := r.Get("pc-1"); comp.Name != "Accounting" {
+		t.Errorf("heartbeat renamed the computer back to %q", comp.Name)
+	}
+}
+
+func TestFind(t *testing.T) {
+	r, err := Open("", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Touch(Info{ID: "pc-1", Name: "Office PC", Tags: []string{"office"}, Group: "Almaty", Owner: "ivan"})
+	r.Touch(Info{ID: "srv-1", Name: "Server", Tags: []string{"server", "office"}, Group: "Astana"})
+	r.Touch(Info{ID: "pc-2", Name: "Home PC", Owner: "anna"})
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{nil, []string{"pc-2", "pc-1", "srv-1"}},
+		{[]string{"tag:office"}, []string{"pc-1", "srv-1"}},
+		{[]string{"tag:office", "group:astana"}, []string{"srv-1"}},
+		{[]string{"owner:IVAN"}, []string{"pc-1"}},
+		{[]string{"pc"}, []string{"pc-2", "pc-1"}},
+		{[]string{"home", "pc"}, []string{"pc-2"}},
+		{[]string{"srv-1"}, []string{"srv-1"}},
+		{[]string{"tag:missing"}, nil},
+	}
+
+	for _, tt := range tests {
+		var ids []string
+		for _, comp := range r.Find(ParseFilter(tt.args)) {
+			ids = append(ids, comp.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("Find(%q) = %q, want %q", tt.args, ids, tt.want)
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	got := ParseFilter([]string{"Tag:office", "group:almaty", "owner:ivan", "web", "site:x"})
+	want := Filter{Tag: "office", Group: "almaty", Owner: "ivan", Name: "web site:x"}
+	if got != want {
+		t.Errorf("ParseFilter() = %+v, want %+v", got, want)
+	}
+	if !ParseFilter(nil).Empty() {
+		t.Error("filter without arguments is not empty")
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"system-monitor/config"
+	"system-monitor/i18n"
+	"system-monitor/scheduler"
+	"time"
+)
+
+const (
+	// configPollInterval is how often the config file is checked for changes
+	configPollInterval = 5 * time.Second
+	// reloadGrace is how long subsystems started with a reloaded config must
+	// run without failing before the old config is given up
+	reloadGrace = 3 * time.Second
+)
+
+// fileState identifies a version of a file; the zero value means missing
+type fileState struct {
+	modTime int64
+	size    int64
+}
+
+// statFile returns the current state of the file at path
+func statFile(path string) fileState {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{modTime: info.ModTime().UnixNano(), size: info.Size()}
+}
+
+// watchConfig signals when the config file should be reloaded: on SIGHUP,
+// or once the file has changed and then stayed the same for one poll
+// interval, so a file still being written is not picked up
+func watchConfig(ctx context.Context, path string) <-chan struct{} {
+	reloads := make(chan struct{}, 1)
+	trigger := func() {
+		select {
+		case reloads <- struct{}{}:
+		default:
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+
+		ticker := time.NewTicker(configPollInterval)
+		defer ticker.Stop()
+
+		last := statFile(path)
+		changed := false
+		for {
+			select {
+			case <-hup:
+				log.Println("Получен SIGHUP, перезагрузка конфигурации")
+				last, changed = statFile(path), false
+				trigger()
+			case <-ticker.C:
+				current := statFile(path)
+				if current != last {
+					last, changed = current, true
+					continue
+				}
+				if changed && current != (fileState{}) {
+					log.Println("Файл конфигурации изменен, перезагрузка")
+					changed = false
+					trigger()
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return reloads
+}
+
+// reload loads the config file and, if it changed, restarts the subsystems
+// with it. A config that is invalid or cannot be applied is reported to the
+// chat and the running service is kept, or started again if the new one
+// failed. An error means the running service did not stop in time, so
+// neither old nor new subsystems may run any more.
+func reload(ctx context.Context, svc *service, path string) (*service, error) {
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		rejectReload(svc, err)
+		return svc, nil
+	}
+
+	changes := config.Changes(svc.cfg, cfg)
+	if len(changes) == 0 {
+		log.Println("Конфигурация не изменилась")
+		return svc, nil
+	}
+	log.Printf("Конфигурация изменена: %s", strings.Join(changes, "; "))
+
+	if keys := config.RestartRequired(svc.cfg, cfg); len(keys) > 0 {
+		rejectReload(svc, fmt.Errorf("%s can only be changed by restarting the service", strings.Join(keys, ", ")))
+		return svc, nil
+	}
+
+	if err := checkConfig(svc.cfg, cfg); err != nil {
+		rejectReload(svc, err)
+		return svc, nil
+	}
+
+	// Two generations must never run at once, e.g. two pollers taking
+	// turns at the same updates
+	if !svc.stop() {
+		err := fmt.Errorf("subsystems did not stop within %v", shutdownTimeout)
+		svc.notify("app.reload_failed", svc.cfg.ComputerName, html.EscapeString(err.Error()))
+		return svc, err
+	}
+	if ctx.Err() != nil {
+		// Shutting down anyway; let the caller finish
+		return svc, nil
+	}
+
+	i18n.SetLocation(cfg.Location())
+	next := startService(ctx, cfg)
+	if err := settle(ctx, next); err != nil {
+		log.Printf("Подсистемы не запустились с новой конфигурацией, возврат к прежней: %v", err)
+		if !next.stop() {
+			return next, fmt.Errorf("subsystems did not stop within %v", shutdownTimeout)
+		}
+
+		i18n.SetLocation(svc.cfg.Location())
+		prev := startService(ctx, svc.cfg)
+		prev.notify("app.reload_failed", svc.cfg.ComputerName, html.EscapeString(err.Error()))
+		return prev, nil
+	}
+
+	lines := make([]string, len(changes))
+	for i, change := range changes {
+		lines[i] = "• " + html.EscapeString(change)
+	}
+	next.notify("app.reloaded", cfg.ComputerName, strings.Join(lines, "\n"))
+
+	return next, nil
+}
+
+// rejectReload reports a config that was not applied
+func rejectReload(svc *service, err error) {
+	log.Printf("Ошибка перезагрузки конфигурации, продолжаем со старой: %v", err)
+	svc.notify("app.reload_failed", svc.cfg.ComputerName, html.EscapeString(err.Error()))
+}
+
+// checkConfig tries what could fail when the subsystems start with next,
+// without starting them: schedules are registered with a scheduler that never
+// runs, the webhook certificate is loaded and the listen addresses that prev
+// does not hold yet are bound and released
+func checkConfig(prev, next *config.Config) error {
+	if err := scheduler.Check(next); err != nil {
+		return err
+	}
+
+	hook := next.Webhook
+	if next.Mode != config.ModeAgent && hook.Enabled() && hook.CertFile != "" {
+		if _, err := tls.LoadX509KeyPair(hook.CertFile, hook.KeyFile); err != nil {
+			return fmt.Errorf("failed to load webhook certificate: %w", err)
+		}
+	}
+
+	// The running service releases its own addresses when it stops
+	held := make(map[string]bool)
+	for _, addr := range listenAddrs(prev) {
+		held[addr] = true
+	}
+
+	used := make(map[string]bool)
+	for _, addr := range listenAddrs(next) {
+		if used[addr] {
+			return fmt.Errorf("address %s is used twice", addr)
+		}
+		used[addr] = true
+		if held[addr] {
+			continue
+		}
+
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on %s: %w", addr, err)
+		}
+		listener.Close()
+	}
+
+	return nil
+}
+
+// listenAddrs returns the addresses the service listens on with cfg
+func listenAddrs(cfg *config.Config) []string {
+	var addrs []string
+	if cfg.Mode == config.ModeHub {
+		addrs = append(addrs, cfg.Hub.Listen)
+	}
+	if cfg.Mode != config.ModeAgent && cfg.Webhook.Enabled() {
+		addrs = append(addrs, cfg.Webhook.Listen)
+	}
+	if cfg.MetricsListen != "" {
+		addrs = append(addrs, cfg.MetricsListen)
+	}
+	return addrs
+}
+
+// settle waits reloadGrace for a started service and returns the error of a
+// subsystem that failed meanwhile, such as a listener that could not bind
+func settle(ctx context.Context, svc *service) error {
+	timer := time.NewTimer(reloadGrace)
+	defer timer.Stop()
+
+	select {
+	case <-svc.services.Done():
+		if ctx.Err() != nil {
+			return nil
+		}
+		return svc.services.Err()
+	case <-timer.C:
+		return nil
+	}
+}
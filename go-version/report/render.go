@@ -0,0 +1,248 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"strings"
+	"system-monitor/i18n"
+	"system-monitor/monitor"
+)
+
+// Renderer turns a report into a message in some format
+type Renderer interface {
+	Render(r *Report) (string, error)
+}
+
+// Supported output formats
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+	FormatJSON     = "json"
+)
+
+// HTML returns a renderer producing the subset of HTML supported by Telegram
+func HTML(loc *i18n.Localizer) Renderer {
+	return treeRenderer{loc: loc, bold: htmlBold, escape: html.EscapeString}
+}
+
+// Markdown returns a renderer producing Markdown
+func Markdown(loc *i18n.Localizer) Renderer {
+	return treeRenderer{loc: loc, bold: markdownBold, escape: markdownEscape}
+}
+
+// Text returns a renderer producing plain text
+func Text(loc *i18n.Localizer) Renderer {
+	return treeRenderer{loc: loc, bold: plain, escape: plain}
+}
+
+// JSON returns a renderer producing indented JSON, which is not localized
+func JSON() Renderer {
+	return jsonRenderer{}
+}
+
+// NewRenderer returns the renderer for a format name
+func NewRenderer(format string, loc *i18n.Localizer) (Renderer, error) {
+	switch format {
+	case FormatHTML:
+		return HTML(loc), nil
+	case FormatMarkdown, "md":
+		return Markdown(loc), nil
+	case FormatText:
+		return Text(loc), nil
+	case FormatJSON:
+		return JSON(), nil
+	}
+	return nil, fmt.Errorf("unknown format %q (expected html, markdown, text or json)", format)
+}
+
+// jsonRenderer renders the report as indented JSON
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(r *Report) (string, error) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal report: %w", err)
+	}
+	return string(data), nil
+}
+
+// treeRenderer renders the report as sections with tree-style lists; the
+// markup functions decide how headings are emphasized and text is escaped
+type treeRenderer struct {
+	loc    *i18n.Localizer
+	bold   func(string) string
+	escape func(string) string
+}
+
+func (t treeRenderer) Render(r *Report) (string, error) {
+	var b strings.Builder
+	loc := t.loc
+
+	// Header
+	b.WriteString("📊 " + t.bold(loc.T("report.title")) + "\n\n")
+
+	if r.Computer != "" {
+		fmt.Fprintf(&b, "🖥️ %s %s\n", t.bold(loc.T("report.computer")), t.escape(r.Computer))
+	}
+	fmt.Fprintf(&b, "🕐 %s %s\n\n", t.bold(loc.T("report.time")), loc.DateTime(r.Time))
+
+	if r.Network != nil {
+		t.section(&b, "🌐", loc.T("report.network"), []string{
+			loc.T("report.hostname", t.escape(r.Network.Hostname)),
+			loc.T("report.local_ip", t.escape(r.Network.LocalIP)),
+			loc.T("report.external_ip", t.escape(r.Network.ExternalIP)),
+		})
+	}
+
+	if r.CPU != nil {
+		t.section(&b, "💻", loc.T("report.cpu"), []string{
+			loc.T("report.cores", r.CPU.Count),
+			loc.T("report.load", r.CPU.Percent),
+		})
+	}
+
+	if r.Memory != nil {
+		t.section(&b, "🧠", loc.T("report.memory"), []string{
+			loc.T("report.total", loc.Bytes(r.Memory.Total)),
+			loc.T("report.used", loc.Bytes(r.Memory.Used), r.Memory.Percent),
+			loc.T("report.available", loc.Bytes(r.Memory.Available)),
+		})
+	}
+
+	if len(r.Disks) > 0 {
+		t.disks(&b, r.Disks)
+	}
+
+	if r.Trend != nil {
+		t.trend(&b, r)
+	}
+
+	if len(r.Forecasts) > 0 {
+		var lines []string
+		for _, forecast := range r.Forecasts {
+			lines = append(lines, loc.T("report.forecast_line",
+				t.escape(forecast.Mountpoint), math.Ceil(forecast.DaysLeft), t.formatDelta(int64(forecast.BytesPerDay))))
+		}
+		t.section(&b, "🔮", loc.T("report.forecast"), lines)
+	}
+
+	if len(r.Services) > 0 {
+		var lines []string
+		for _, service := range r.Services {
+			icon, state := "✅", service.State
+			if !service.Running {
+				icon = "❌"
+			}
+			if service.Error != "" {
+				state = service.Error
+			}
+			lines = append(lines, loc.T("report.service_line", icon, t.escape(service.Name), t.escape(state)))
+		}
+		t.section(&b, "🧩", loc.T("report.services"), lines)
+	}
+
+	if len(r.TopCPU) > 0 {
+		var lines []string
+		for _, proc := range r.TopCPU {
+			lines = append(lines, loc.T("report.top_cpu_line", t.escape(proc.Name), proc.CPUPercent, proc.PID))
+		}
+		t.section(&b, "⚡", loc.T("report.top_cpu"), lines)
+	}
+
+	if len(r.TopMemory) > 0 {
+		var lines []string
+		for _, proc := range r.TopMemory {
+			lines = append(lines, loc.T("report.top_memory_line", t.escape(proc.Name), proc.MemoryMB, proc.MemoryPercent))
+		}
+		t.section(&b, "🔥", loc.T("report.top_memory"), lines)
+	}
+
+	if len(r.Errors) > 0 {
+		var lines []string
+		for _, e := range r.Errors {
+			lines = append(lines, t.escape(e.String()))
+		}
+		t.section(&b, "⚠️", loc.T("report.errors"), lines)
+	}
+
+	return strings.TrimRight(b.String(), "\n"), nil
+}
+
+// section writes a heading followed by a tree-style list
+func (t treeRenderer) section(b *strings.Builder, icon, title string, lines []string) {
+	fmt.Fprintf(b, "%s %s\n", icon, t.bold(title))
+	for i, line := range lines {
+		fmt.Fprintf(b, "%s %s\n", branch(i, len(lines)), line)
+	}
+	b.WriteString("\n")
+}
+
+// disks writes the disk section with a nested list per mountpoint
+func (t treeRenderer) disks(b *strings.Builder, disks []*monitor.DiskInfo) {
+	loc := t.loc
+	fmt.Fprintf(b, "💾 %s\n", t.bold(loc.T("report.disks")))
+	for i, disk := range disks {
+		isLast := i == len(disks)-1
+		subPrefix := "│ "
+		if isLast {
+			subPrefix = "  "
+		}
+
+		fmt.Fprintf(b, "%s %s\n", branch(i, len(disks)), t.bold(t.escape(disk.Mountpoint)))
+		fmt.Fprintf(b, "%s├ %s\n", subPrefix, loc.T("report.total", loc.Bytes(disk.Total)))
+		fmt.Fprintf(b, "%s├ %s\n", subPrefix, loc.T("report.used", loc.Bytes(disk.Used), disk.Percent))
+		fmt.Fprintf(b, "%s└ %s\n", subPrefix, loc.T("report.free", loc.Bytes(disk.Free)))
+		if !isLast {
+			b.WriteString("\n")
+		}
+	}
+	b.WriteString("\n")
+}
+
+// trend writes the changes recorded over the last 24 hours
+func (t treeRenderer) trend(b *strings.Builder, r *Report) {
+	lines := []string{
+		t.loc.T("report.trend_cpu", r.Trend.CPUAvg, r.Trend.CPUPeak),
+		t.loc.T("report.trend_memory", r.Trend.MemoryAvg, r.Trend.MemoryPeak),
+	}
+	for _, disk := range r.Trend.Disks {
+		lines = append(lines, fmt.Sprintf("%s: %s (%.1f%%)", t.escape(disk.Mountpoint), t.formatDelta(disk.Delta), disk.Percent))
+	}
+	t.section(b, "📈", t.loc.T("report.trend"), lines)
+}
+
+// formatDelta formats a signed byte change, e.g. "+4.20 ГБ"
+func (t treeRenderer) formatDelta(delta int64) string {
+	if delta < 0 {
+		return "-" + t.loc.Bytes(uint64(-delta))
+	}
+	return "+" + t.loc.Bytes(uint64(delta))
+}
+
+// branch returns the tree prefix for item i of n
+func branch(i, n int) string {
+	if i == n-1 {
+		return "└"
+	}
+	return "├"
+}
+
+func htmlBold(s string) string {
+	return "<b>" + s + "</b>"
+}
+
+func markdownBold(s string) string {
+	return "**" + s + "**"
+}
+
+// markdownEscape escapes characters that have a meaning in Markdown
+func markdownEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`).Replace(s)
+}
+
+func plain(s string) string {
+	return s
+}
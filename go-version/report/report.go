@@ -0,0 +1,153 @@
+package report
+
+import (
+	"fmt"
+	"system-monitor/config"
+	"system-monitor/monitor"
+	"system-monitor/storage"
+	"time"
+)
+
+const (
+	// topProcesses is the number of processes listed per section
+	topProcesses = 5
+	// trendPeriod is the window covered by the trend section
+	trendPeriod = 24 * time.Hour
+)
+
+// Report is the data of a system report, independent of its presentation
+type Report struct {
+	Computer  string                  `json:"computer"`
+	Time      time.Time               `json:"time"`
+	Network   *monitor.IPInfo         `json:"network,omitempty"`
+	CPU       *monitor.CPUInfo        `json:"cpu,omitempty"`
+	Memory    *monitor.MemoryInfo     `json:"memory,omitempty"`
+	Disks     []*monitor.DiskInfo     `json:"disks,omitempty"`
+	TopCPU    []*monitor.ProcessInfo  `json:"top_cpu,omitempty"`
+	TopMemory []*monitor.ProcessInfo  `json:"top_memory,omitempty"`
+	Trend     *storage.Trend          `json:"trend,omitempty"`
+	Forecasts []storage.Forecast      `json:"forecasts,omitempty"`
+	Services  []monitor.ServiceStatus `json:"services,omitempty"`
+	Errors    []CollectionError       `json:"errors,omitempty"`
+}
+
+// CollectionError records a section that could not be collected
+type CollectionError struct {
+	Section string `json:"section"`
+	Message string `json:"message"`
+}
+
+// Report sections that profiles choose from
+const (
+	sectionNetwork   = "network"
+	sectionCPU       = "cpu"
+	sectionMemory    = "memory"
+	sectionDisks     = "disks"
+	sectionProcesses = "processes"
+	sectionServices  = "services"
+	sectionTrend     = "trend"
+	sectionForecast  = "forecast"
+)
+
+// profileSections lists the sections collected for each report profile
+var profileSections = map[string][]string{
+	config.ProfileFull: {
+		sectionNetwork, sectionCPU, sectionMemory, sectionDisks,
+		sectionProcesses, sectionServices, sectionTrend, sectionForecast,
+	},
+	config.ProfileBrief:     {sectionCPU, sectionMemory, sectionDisks, sectionServices},
+	config.ProfileDisks:     {sectionDisks, sectionForecast},
+	config.ProfileProcesses: {sectionProcesses},
+}
+
+// Collect gathers a full report for this computer, including the services
+// it watches. When store is not nil the report includes the 24h trend and
+// disk forecasts from history.
+func Collect(cfg *config.Config, store *storage.Store) *Report {
+	return CollectProfile(cfg, store, config.ProfileFull)
+}
+
+// CollectProfile gathers only the sections of a report profile; an
+// unknown profile gives a full report
+func CollectProfile(cfg *config.Config, store *storage.Store, profile string) *Report {
+	sections, ok := profileSections[profile]
+	if !ok {
+		sections = profileSections[config.ProfileFull]
+	}
+	include := make(map[string]bool)
+	for _, section := range sections {
+		include[section] = true
+	}
+
+	r := &Report{
+		Computer: cfg.ComputerName,
+		Time:     time.Now().In(cfg.Location()),
+	}
+
+	var err error
+	if include[sectionNetwork] {
+		if r.Network, err = monitor.GetIPInfo(); err != nil {
+			r.addError("network", err)
+		}
+	}
+	if include[sectionCPU] {
+		if r.CPU, err = monitor.GetCPUInfo(); err != nil {
+			r.addError("cpu", err)
+		}
+	}
+	if include[sectionMemory] {
+		if r.Memory, err = monitor.GetMemoryInfo(); err != nil {
+			r.addError("memory", err)
+		}
+	}
+	if include[sectionDisks] {
+		if r.Disks, err = monitor.GetDiskInfo(); err != nil {
+			r.addError("disks", err)
+		}
+	}
+	if include[sectionProcesses] {
+		if r.TopCPU, err = monitor.GetTopProcessesByCPU(topProcesses); err != nil {
+			r.addError("top_cpu", err)
+		}
+		if r.TopMemory, err = monitor.GetTopProcessesByMemory(topProcesses); err != nil {
+			r.addError("top_memory", err)
+		}
+	}
+	if include[sectionServices] && len(cfg.Services) > 0 {
+		r.Services = monitor.GetServices(cfg.Services)
+	}
+
+	if store == nil {
+		return r
+	}
+
+	if include[sectionTrend] {
+		if r.Trend, err = store.Trend(r.Time.Add(-trendPeriod), r.Time); err != nil {
+			r.addError("trend", err)
+		}
+	}
+
+	if include[sectionForecast] {
+		forecasts, err := store.ForecastDisks(r.Time)
+		if err != nil {
+			r.addError("forecast", err)
+		}
+		// Only disks that are filling up have a finite forecast
+		for _, forecast := range forecasts {
+			if forecast.Growing() {
+				r.Forecasts = append(r.Forecasts, forecast)
+			}
+		}
+	}
+
+	return r
+}
+
+func (r *Report) addError(section string, err error) {
+	r.Errors = append(r.Errors, CollectionError{Section: section, Message: err.Error()})
+}
+
+// String implements fmt.Stringer for log messages
+func (e CollectionError) String() string {
+	return fmt.Sprintf("%s: %s", e.Section, e.Message)
+}
@@ -1,64 +1,152 @@
 package scheduler
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"os"
-	"os/signal"
-	"syscall"
 	"system-monitor/config"
+	"system-monitor/i18n"
+	"system-monitor/notify"
+	"system-monitor/report"
+	"system-monitor/storage"
 	"system-monitor/telegram"
+	"time"
 	
 	"github.com/go-co-op/gocron"
 )
 
-// Run starts the scheduler
-func Run(cfg *config.Config) error {
-	s := gocron.NewScheduler(nil)
-
-	// Schedule daily report
-	_, err := s.Every(1).Day().At(cfg.ScheduleTime).Do(func() {
-		log.Printf("Создание и отправка отчета...")
-		if err := sendReport(cfg); err != nil {
-			log.Printf("Ошибка при отправке отчета: %v", err)
-		} else {
-			log.Printf("Отчет успешно отправлен")
+// Run starts a job for every report schedule, sending reports through the
+// notifier, and stops the jobs when ctx is done. The store may be nil when
+// history is unavailable.
+func Run(ctx context.Context, cfg *config.Config, store *storage.Store, notifier notify.Notifier) error {
+	var schedulers []*gocron.Scheduler
+	stopAll := func() {
+		for _, s := range schedulers {
+			s.Stop()
 		}
-	})
+	}
 
-	if err != nil {
-		return fmt.Errorf("failed to schedule task: %w", err)
+	for _, schedule := range cfg.ReportSchedules() {
+		s, err := start(cfg, store, notifier, schedule)
+		if err != nil {
+			stopAll()
+			return fmt.Errorf("failed to schedule report %q: %w", schedule.Name, err)
+		}
+		schedulers = append(schedulers, s)
 	}
 
-	log.Printf("Сервис запущен. Отправка отчетов запланирована на %s", cfg.ScheduleTime)
+	log.Printf("Сервис запущен. Запланировано отчетов: %d", len(schedulers))
+
+	<-ctx.Done()
+	stopAll()
+
+	return nil
+}
+
+// Check registers every report schedule with a scheduler that is never
+// started, so that a config is known to be accepted before it is applied
+func Check(cfg *config.Config) error {
+	for _, schedule := range cfg.ReportSchedules() {
+		if _, _, err := build(cfg, nil, nil, schedule); err != nil {
+			return fmt.Errorf("failed to schedule report %q: %w", schedule.Name, err)
+		}
+	}
+	return nil
+}
+
+// start registers a schedule with its own scheduler, so that every
+// schedule runs in its own time zone
+func start(cfg *config.Config, store *storage.Store, notifier notify.Notifier, schedule config.Schedule) (*gocron.Scheduler, error) {
+	s, timing, err := build(cfg, store, notifier, schedule)
+	if err != nil {
+		return nil, err
+	}
 
-	// Start the scheduler
 	s.StartAsync()
+	log.Printf("Отчет %s (%s): %s, часовой пояс %s", schedule.Name, schedule.ProfileName(), timing, schedule.Location())
 
-	// Wait for interrupt signal
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	return s, nil
+}
 
-	log.Println("Остановка сервиса...")
-	s.Stop()
+// build creates a scheduler with the job of a schedule without starting it,
+// and describes the timing for the log
+func build(cfg *config.Config, store *storage.Store, notifier notify.Notifier, schedule config.Schedule) (*gocron.Scheduler, string, error) {
+	s := gocron.NewScheduler(schedule.Location())
 
-	return nil
+	job := func() {
+		sendReport(cfg, store, notifier, schedule)
+	}
+
+	var err error
+	var timing string
+	switch {
+	case schedule.Cron != "":
+		_, err = s.Cron(schedule.Cron).Do(job)
+		timing = "cron " + schedule.Cron
+	case schedule.Every != "":
+		_, err = s.Every(schedule.Interval()).WaitForSchedule().Do(job)
+		timing = "каждые " + schedule.Interval().String()
+	default:
+		_, err = s.Every(1).Day().At(schedule.At).Do(job)
+		timing = "ежедневно в " + schedule.At
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return s, timing, nil
 }
 
-// RunTest sends a test report immediately
-func RunTest(cfg *config.Config) error {
-	log.Println("Запуск в тестовом режиме")
-	return sendReport(cfg)
+// sendReport builds the report of a schedule and sends it to the schedule's
+// chats, or through routing when it has none
+func sendReport(cfg *config.Config, store *storage.Store, notifier notify.Notifier, schedule config.Schedule) {
+	if schedule.Excluded(time.Now().In(schedule.Location()), cfg.Holidays) {
+		log.Printf("Отчет %s пропущен: выходной или праздничный день", schedule.Name)
+		return
+	}
+
+	log.Printf("Создание отчета %s...", schedule.Name)
+
+	chats := schedule.Chats
+	if len(chats) == 0 {
+		// An empty chat ID leaves the choice of channels to routing
+		chats = []string{""}
+	}
+
+	r := report.CollectProfile(cfg, store, schedule.ProfileName())
+	for _, chatID := range chats {
+		languageChat := chatID
+		if languageChat == "" {
+			languageChat = cfg.ChatID
+		}
+
+		message, err := report.HTML(i18n.ForChat(languageChat, cfg.Language)).Render(r)
+		if err != nil {
+			log.Printf("Ошибка при создании отчета %s: %v", schedule.Name, err)
+			return
+		}
+
+		msg := notify.Message{Kind: config.KindReport, ChatID: chatID, Text: message}
+		if err := notifier.Notify(context.Background(), msg); err != nil {
+			log.Printf("Ошибка при отправке отчета %s: %v", schedule.Name, err)
+		}
+	}
+
+	log.Printf("Отчет %s поставлен в очередь отправки", schedule.Name)
 }
 
-func sendReport(cfg *config.Config) error {
-	report, err := telegram.CreateReport(cfg.ComputerName)
+// RunTest sends a full test report to chat_id immediately, bypassing the
+// outbox and routing
+func RunTest(cfg *config.Config, store *storage.Store, client *telegram.Client) error {
+	log.Println("Запуск в тестовом режиме")
+
+	loc := i18n.ForChat(cfg.ChatID, cfg.Language)
+	message, err := report.HTML(loc).Render(report.Collect(cfg, store))
 	if err != nil {
 		return fmt.Errorf("failed to create report: %w", err)
 	}
 
-	if err := telegram.SendMessage(cfg.TelegramToken, cfg.ChatID, report); err != nil {
+	if err := client.SendMessage(context.Background(), cfg.ChatID, message); err != nil {
 		return fmt.Errorf("failed to send message: %w", err)
 	}
 
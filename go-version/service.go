@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"log"
+	"system-monitor/alert"
+	"system-monitor/bot"
+	"system-monitor/config"
+	"system-monitor/exporter"
+	"system-monitor/hub"
+	"system-monitor/i18n"
+	"system-monitor/notify"
+	"system-monitor/outbox"
+	"system-monitor/registry"
+	"system-monitor/scheduler"
+	"system-monitor/storage"
+	"system-monitor/telegram"
+)
+
+// service is the set of subsystems running with one configuration. On a
+// config reload it is stopped and a new one is started, so no subsystem
+// ever works with a mix of old and new settings.
+type service struct {
+	cfg      *config.Config
+	queue    *outbox.Outbox
+	services *group
+}
+
+// startService starts every subsystem enabled in cfg under ctx
+func startService(ctx context.Context, cfg *config.Config) *service {
+	services := newGroup(ctx)
+	store := openStore(cfg)
+
+	// One Telegram client for all subsystems, so they share rate limits
+	client := telegram.NewClient(cfg)
+
+	// Record metric history
+	if store != nil {
+		services.Go("истории метрик", func(ctx context.Context) error {
+			store.Run(ctx)
+			return nil
+		})
+	}
+
+	// Reports and alerts are routed to notification channels and queued on
+	// disk, so they survive network outages and restarts
+	queue, err := outbox.Open(cfg, notify.Channels(cfg, client))
+	if err != nil {
+		log.Printf("Ошибка загрузки очереди сообщений: %v", err)
+	}
+	services.Go("очереди сообщений", func(ctx context.Context) error {
+		queue.Run(ctx)
+		return nil
+	})
+
+	// Run scheduler in background
+	services.Go("планировщика", func(ctx context.Context) error {
+		return scheduler.Run(ctx, cfg, store, queue)
+	})
+
+	// Run alert engine if rules are configured
+	if len(cfg.AlertRules()) > 0 {
+		engine := alert.NewEngine(cfg, store, queue)
+		services.Go("алертов", func(ctx context.Context) error {
+			engine.Run(ctx)
+			return nil
+		})
+	}
+
+	// Serve Prometheus metrics if a listen address is configured
+	if cfg.MetricsListen != "" {
+		// The exporter is optional, so its failure does not stop the service
+		exporterServer := exporter.NewServer(cfg)
+		services.Go("Prometheus exporter", func(ctx context.Context) error {
+			if err := exporterServer.Run(ctx); err != nil {
+				log.Printf("Ошибка Prometheus exporter: %v", err)
+			}
+			return nil
+		})
+	}
+
+	if cfg.Mode == config.ModeAgent {
+		// Agents report to the hub, which owns Telegram polling
+		agent := hub.NewAgent(cfg, store, version)
+		services.Go("агента", func(ctx context.Context) error {
+			agent.Run(ctx)
+			return nil
+		})
+	} else {
+		startInteractive(services, cfg, store, client, queue)
+	}
+
+	return &service{cfg: cfg, queue: queue, services: services}
+}
+
+// startInteractive keeps this computer in the registry, serves the hub API in
+// hub mode and answers Telegram updates by webhook or polling
+func startInteractive(services *group, cfg *config.Config, store *storage.Store, client *telegram.Client, notifier notify.Notifier) {
+	// Keep this computer in the registry with a periodic local heartbeat
+	computers, err := registry.Open(cfg.DataPath("computers.json"), cfg.OfflineThreshold())
+	if err != nil {
+		log.Printf("Ошибка загрузки списка компьютеров: %v", err)
+	}
+	info := registry.Info{
+		ID:    cfg.ComputerID,
+		Name:  cfg.ComputerName,
+		Tags:  cfg.Tags,
+		Group: cfg.Group,
+		Owner: cfg.Owner,
+	}
+	services.Go("списка компьютеров", func(ctx context.Context) error {
+		computers.RunLocal(ctx, info, cfg.Hub.Heartbeat())
+		return nil
+	})
+
+	var hubServer *hub.Server
+	if cfg.Mode == config.ModeHub {
+		hubServer = hub.NewServer(cfg, computers, client, notifier)
+		services.Go("hub API", hubServer.Run)
+	}
+
+	// Receive updates by webhook if configured, otherwise poll if enabled;
+	// a hub always answers commands
+	if cfg.Webhook.Enabled() {
+		log.Println("Interactive mode enabled (webhook)")
+		poller := bot.NewPoller(cfg, store, computers, hubServer, client)
+		services.Go("webhook", poller.StartWebhook)
+	} else if cfg.EnablePolling || cfg.Mode == config.ModeHub {
+		log.Println("Interactive mode enabled")
+		poller := bot.NewPoller(cfg, store, computers, hubServer, client)
+		services.Go("опроса Telegram", func(ctx context.Context) error {
+			poller.StartPolling(ctx)
+			return nil
+		})
+	} else {
+		log.Println("Polling disabled, running in scheduled mode only")
+	}
+}
+
+// openStore opens the metric history, returning nil if it is unavailable
+func openStore(cfg *config.Config) *storage.Store {
+	store, err := storage.Open(cfg)
+	if err != nil {
+		log.Printf("Ошибка открытия истории метрик: %v", err)
+		return nil
+	}
+	return store
+}
+
+// notify queues a status message for the configured chat
+func (s *service) notify(key string, args ...interface{}) {
+	loc := i18n.ForChat(s.cfg.ChatID, s.cfg.Language)
+	msg := notify.Message{Kind: config.KindStatus, Text: loc.T(key, args...)}
+	if err := s.queue.Notify(context.Background(), msg); err != nil {
+		log.Printf("Ошибка сохранения уведомления: %v", err)
+	}
+}
+
+// stop cancels the subsystems and waits for them up to shutdownTimeout. It
+// reports whether all of them stopped.
+func (s *service) stop() bool {
+	if !s.services.Stop(shutdownTimeout) {
+		log.Printf("Не все подсистемы остановились за %v", shutdownTimeout)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// maxForecastPoints bounds the number of points used by the regression,
+// which is quadratic in the number of points
+const maxForecastPoints = 200
+
+// Forecast is the estimated time until a mountpoint fills up
+type Forecast struct {
+	Mountpoint string  `json:"mountpoint"`
+	Total      uint64  `json:"total"`
+	Used       uint64  `json:"used"`
+	Percent    float64 `json:"percent"`
+	// BytesPerDay is the estimated growth rate of used space
+	BytesPerDay float64 `json:"bytes_per_day"`
+	// DaysLeft is +Inf when usage is not growing, which JSON cannot encode,
+	// so only growing forecasts should be marshaled
+	DaysLeft float64 `json:"days_left"`
+}
+
+// Growing reports whether the disk is expected to fill up at all
+func (f Forecast) Growing() bool {
+	return !math.IsInf(f.DaysLeft, 1)
+}
+
+// ForecastDisks estimates when each mountpoint seen in the forecast window
+// will be full, using a Theil-Sen regression of used space over time
+func (s *Store) ForecastDisks(now time.Time) ([]Forecast, error) {
+	samples, err := s.Query(now.Add(-s.forecastWindow), now)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make(map[string][]DiskPoint)
+	for _, sample := range samples {
+		for _, disk := range sample.Disks {
+			series[disk.Mountpoint] = append(series[disk.Mountpoint], DiskPoint{
+				Time:    sample.Time,
+				Used:    disk.Used,
+				Total:   disk.Total,
+				Percent: disk.Percent,
+			})
+		}
+	}
+
+	var forecasts []Forecast
+	for mountpoint, points := range series {
+		if forecast, ok := forecastSeries(mountpoint, points); ok {
+			forecasts = append(forecasts, forecast)
+		}
+	}
+
+	sort.Slice(forecasts, func(i, j int) bool {
+		return forecasts[i].Mountpoint < forecasts[j].Mountpoint
+	})
+
+	return forecasts, nil
+}
+
+// forecastSeries computes the forecast for one mountpoint; it needs at
+// least two points spanning a non-zero time range
+func forecastSeries(mountpoint string, points []DiskPoint) (Forecast, bool) {
+	if len(points) < 2 || !points[len(points)-1].Time.After(points[0].Time) {
+		return Forecast{}, false
+	}
+
+	last := points[len(points)-1]
+	forecast := Forecast{
+		Mountpoint:  mountpoint,
+		Total:       last.Total,
+		Used:        last.Used,
+		Percent:     last.Percent,
+		BytesPerDay: theilSen(thin(points, maxForecastPoints)),
+		DaysLeft:    math.Inf(1),
+	}
+
+	if forecast.BytesPerDay > 0 && last.Total > last.Used {
+		forecast.DaysLeft = float64(last.Total-last.Used) / forecast.BytesPerDay
+	} else if forecast.BytesPerDay > 0 {
+		forecast.DaysLeft = 0
+	}
+
+	return forecast, true
+}
+
+// theilSen returns the median of pairwise slopes in bytes per day, which
+// ignores one-off spikes such as a temporary file being written and removed
+func theilSen(points []DiskPoint) float64 {
+	var slopes []float64
+	for i := 0; i < len(points); i++ {
+		for j := i + 1; j < len(points); j++ {
+			days := points[j].Time.Sub(points[i].Time).Hours() / 24
+			if days <= 0 {
+				continue
+			}
+			slopes = append(slopes, (float64(points[j].Used)-float64(points[i].Used))/days)
+		}
+	}
+
+	if len(slopes) == 0 {
+		return 0
+	}
+
+	sort.Float64s(slopes)
+	mid := len(slopes) / 2
+	if len(slopes)%2 == 0 {
+		return (slopes[mid-1] + slopes[mid]) / 2
+	}
+	return slopes[mid]
+}
+
+// thin keeps at most n evenly spaced points, always including the last one
+func thin(points []DiskPoint, n int) []DiskPoint {
+	if len(points) <= n {
+		return points
+	}
+
+	result := make([]DiskPoint, 0, n)
+	step := float64(len(points)-1) / float64(n-1)
+	for i := 0; i < n; i++ {
+		result = append(result, points[int(math.Round(float64(i)*step))])
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// daily returns points one day apart with the given used bytes
+func daily(used ...uint64) []DiskPoint {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := make([]DiskPoint, len(used))
+	for i, u := range used {
+		points[i] = DiskPoint{Time: start.AddDate(0, 0, i), Used: u}
+	}
+	return points
+}
+
+func TestTheilSen(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []DiskPoint
+		want   float64
+	}{
+		{"no points", nil, 0},
+		{"one point", daily(100), 0},
+		{"steady growth", daily(100, 200, 300, 400), 100},
+		{"shrinking", daily(400, 300, 200, 100), -100},
+		{"flat", daily(500, 500, 500), 0},
+		{"ignores spike", daily(100, 200, 5000, 400, 500), 100},
+		{"median of three slopes", daily(0, 100, 300), 150},
+		{"median of six slopes", daily(0, 0, 300, 300), 125},
+		{"same time", []DiskPoint{{Time: time.Unix(0, 0), Used: 1}, {Time: time.Unix(0, 0), Used: 2}}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := theilSen(tt.points); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("theilSen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestThin(t *testing.T) {
+	points := daily(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+
+	tests := []struct {
+		name string
+		n    int
+		want []uint64
+	}{
+		{"fewer than n", 20, []uint64{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{"two", 2, []uint64{0, 9}},
+		{"four", 4, []uint64{0, 3, 6, 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := thin(points, tt.n)
+			if len(got) != len(tt.want) {
+				t.Fatalf("thin() returned %d points, want %d", len(got), len(tt.want))
+			}
+			for i, point := range got {
+				if point.Used != tt.want[i] {
+					t.Errorf("point %d = %d, want %d", i, point.Used, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"log"
+	"system-monitor/monitor"
+	"time"
+)
+
+// topProcesses is the number of processes recorded with each sample
+const topProcesses = 5
+
+// Sample is one recorded set of metrics
+type Sample struct {
+	Time       time.Time       `json:"t"`
+	CPU        float64         `json:"cpu"`
+	CPUMax     float64         `json:"cpu_max,omitempty"`
+	Memory     float64         `json:"mem"`
+	MemoryMax  float64         `json:"mem_max,omitempty"`
+	MemoryUsed uint64          `json:"mem_used"`
+	Disks      []DiskSample    `json:"disks,omitempty"`
+	Processes  []ProcessSample `json:"procs,omitempty"`
+}
+
+// DiskSample is the usage of one mountpoint
+type DiskSample struct {
+	Mountpoint string  `json:"mount"`
+	Total      uint64  `json:"total"`
+	Used       uint64  `json:"used"`
+	Percent    float64 `json:"pct"`
+}
+
+// ProcessSample is a process recorded among the top consumers
+type ProcessSample struct {
+	PID        int32   `json:"pid"`
+	Name       string  `json:"name"`
+	CPUPercent float64 `json:"cpu"`
+	MemoryMB   float64 `json:"mem_mb"`
+}
+
+// DiskPoint is a single value of a per-mountpoint series
+type DiskPoint struct {
+	Time    time.Time
+	Used    uint64
+	Total   uint64
+	Percent float64
+}
+
+// PeakCPU returns the highest CPU load covered by the sample
+func (s Sample) PeakCPU() float64 {
+	if s.CPUMax > s.CPU {
+		return s.CPUMax
+	}
+	return s.CPU
+}
+
+// PeakMemory returns the highest memory usage covered by the sample
+func (s Sample) PeakMemory() float64 {
+	if s.MemoryMax > s.Memory {
+		return s.MemoryMax
+	}
+	return s.Memory
+}
+
+// Disk returns the sample for a mountpoint, if recorded
+func (s Sample) Disk(mountpoint string) (DiskSample, bool) {
+	for _, d := range s.Disks {
+		if d.Mountpoint == mountpoint {
+			return d, true
+		}
+	}
+	return DiskSample{}, false
+}
+
+// Collect takes a sample of the current system state, measuring CPU load
+// since the previous sample taken with cpu
+func Collect(cpu *monitor.CPUSampler) Sample {
+	sample := Sample{Time: time.Now()}
+
+	if cpuInfo, err := cpu.Info(); err == nil {
+		sample.CPU = cpuInfo.Percent
+	} else {
+		log.Printf("Ошибка получения данных CPU: %v", err)
+	}
+
+	if memInfo, err := monitor.GetMemoryInfo(); err == nil {
+		sample.Memory = memInfo.Percent
+		sample.MemoryUsed = memInfo.Used
+	} else {
+		log.Printf("Ошибка получения данных памяти: %v", err)
+	}
+
+	if disks, err := monitor.GetDiskInfo(); err == nil {
+		for _, disk := range disks {
+			sample.Disks = append(sample.Disks, DiskSample{
+				Mountpoint: disk.Mountpoint,
+				Total:      disk.Total,
+				Used:       disk.Used,
+				Percent:    disk.Percent,
+			})
+		}
+	} else {
+		log.Printf("Ошибка получения данных дисков: %v", err)
+	}
+
+	if procs, err := monitor.GetTopProcessesByCPU(topProcesses); err == nil {
+		for _, proc := range procs {
+			sample.Processes = append(sample.Processes, ProcessSample{
+				PID:        proc.PID,
+				Name:       proc.Name,
+				CPUPercent: proc.CPUPercent,
+				MemoryMB:   proc.MemoryMB,
+			})
+		}
+	}
+
+	return sample
+}
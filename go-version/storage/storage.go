@@ -0,0 +1,343 @@
+package storage
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"system-monitor/config"
+	"system-monitor/monitor"
+	"time"
+)
+
+// dayLayout is the date format used for daily history files
+const dayLayout = "2006-01-02"
+
+// Store keeps metric samples in daily JSON-lines files
+type Store struct {
+	dir                string
+	interval           time.Duration
+	retention          time.Duration
+	downsampleAfter    time.Duration
+	downsampleInterval time.Duration
+	forecastWindow     time.Duration
+	mu                 sync.Mutex
+}
+
+// Open opens (creating if needed) the history store configured in cfg
+func Open(cfg *config.Config) (*Store, error) {
+	dir := cfg.History.Dir
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create history dir: %w", err)
+	}
+
+	return &Store{
+		dir:                dir,
+		interval:           cfg.History.SampleInterval(),
+		retention:          cfg.History.RetentionPeriod(),
+		downsampleAfter:    cfg.History.DownsampleAge(),
+		downsampleInterval: cfg.History.DownsampleStep(),
+		forecastWindow:     cfg.History.ForecastPeriod(),
+	}, nil
+}
+
+// Run records a sample every interval and periodically compacts the store
+// until ctx is done
+func (s *Store) Run(ctx context.Context) {
+	log.Printf("Запись истории метрик: интервал %v, хранение %v", s.interval, s.retention)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	cpu := monitor.NewCPUSampler()
+	lastCompact := time.Time{}
+	for {
+		if err := s.Append(Collect(cpu)); err != nil {
+			log.Printf("Ошибка записи истории: %v", err)
+		}
+
+		if time.Since(lastCompact) >= time.Hour {
+			if err := s.Compact(time.Now()); err != nil {
+				log.Printf("Ошибка сжатия истории: %v", err)
+			}
+			lastCompact = time.Now()
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Append writes a sample to the file of its day
+func (s *Store) Append(sample Sample) error {
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sample: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.dayPath(sample.Time), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write sample: %w", err)
+	}
+
+	return nil
+}
+
+// Query returns samples in [from, to] ordered by time
+func (s *Store) Query(from, to time.Time) ([]Sample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days, err := s.days()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Sample
+	for _, day := range days {
+		// A day file covers [day, day+24h)
+		if day.Add(24*time.Hour).Before(from) || day.After(to) {
+			continue
+		}
+
+		samples, err := readSamples(s.dayPath(day))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sample := range samples {
+			if !sample.Time.Before(from) && !sample.Time.After(to) {
+				result = append(result, sample)
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Time.Before(result[j].Time)
+	})
+
+	return result, nil
+}
+
+// DiskSeries returns the usage history of one mountpoint in [from, to]
+func (s *Store) DiskSeries(mountpoint string, from, to time.Time) ([]DiskPoint, error) {
+	samples, err := s.Query(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []DiskPoint
+	for _, sample := range samples {
+		if disk, ok := sample.Disk(mountpoint); ok {
+			points = append(points, DiskPoint{
+				Time:    sample.Time,
+				Used:    disk.Used,
+				Total:   disk.Total,
+				Percent: disk.Percent,
+			})
+		}
+	}
+
+	return points, nil
+}
+
+// Mountpoints returns the mountpoints seen in [from, to]
+func (s *Store) Mountpoints(from, to time.Time) ([]string, error) {
+	samples, err := s.Query(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var result []string
+	for _, sample := range samples {
+		for _, disk := range sample.Disks {
+			if !seen[disk.Mountpoint] {
+				seen[disk.Mountpoint] = true
+				result = append(result, disk.Mountpoint)
+			}
+		}
+	}
+
+	sort.Strings(result)
+	return result, nil
+}
+
+// Compact removes days past retention and downsamples old days
+func (s *Store) Compact(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days, err := s.days()
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		end := day.Add(24 * time.Hour)
+		path := s.dayPath(day)
+
+		if now.Sub(end) > s.retention {
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("failed to remove history file: %w", err)
+			}
+			continue
+		}
+
+		if now.Sub(end) > s.downsampleAfter {
+			if err := s.downsample(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// downsample rewrites a day file with one aggregated sample per bucket
+func (s *Store) downsample(path string) error {
+	samples, err := readSamples(path)
+	if err != nil {
+		return err
+	}
+
+	buckets := make(map[time.Time][]Sample)
+	var keys []time.Time
+	for _, sample := range samples {
+		key := sample.Time.Truncate(s.downsampleInterval)
+		if _, exists := buckets[key]; !exists {
+			keys = append(keys, key)
+		}
+		buckets[key] = append(buckets[key], sample)
+	}
+
+	// Nothing to merge, the file is already downsampled
+	if len(keys) == len(samples) {
+		return nil
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Before(keys[j]) })
+
+	var b strings.Builder
+	for _, key := range keys {
+		data, err := json.Marshal(aggregate(key, buckets[key]))
+		if err != nil {
+			return fmt.Errorf("failed to marshal sample: %w", err)
+		}
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write history file: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace history file: %w", err)
+	}
+
+	return nil
+}
+
+// aggregate merges samples into one: averages with peaks, and the last
+// disk and process readings of the bucket
+func aggregate(t time.Time, samples []Sample) Sample {
+	result := Sample{Time: t}
+
+	var memoryUsed float64
+	for _, sample := range samples {
+		result.CPU += sample.CPU
+		result.Memory += sample.Memory
+		memoryUsed += float64(sample.MemoryUsed)
+		result.CPUMax = max(result.CPUMax, sample.PeakCPU())
+		result.MemoryMax = max(result.MemoryMax, sample.PeakMemory())
+	}
+
+	n := float64(len(samples))
+	result.CPU /= n
+	result.Memory /= n
+	result.MemoryUsed = uint64(memoryUsed / n)
+
+	last := samples[len(samples)-1]
+	result.Disks = last.Disks
+	result.Processes = last.Processes
+
+	return result
+}
+
+// days lists the days that have a history file
+func (s *Store) days() ([]time.Time, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history dir: %w", err)
+	}
+
+	var days []time.Time
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		day, err := time.ParseInLocation(dayLayout, name, time.Local)
+		if err != nil {
+			continue
+		}
+		days = append(days, day)
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	return days, nil
+}
+
+// dayPath returns the file holding samples of the day containing t
+func (s *Store) dayPath(t time.Time) string {
+	return filepath.Join(s.dir, t.Local().Format(dayLayout)+".jsonl")
+}
+
+// readSamples reads all samples from a day file, skipping corrupt lines
+func readSamples(path string) ([]Sample, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer file.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			continue
+		}
+		samples = append(samples, sample)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+
+	return samples, nil
+}
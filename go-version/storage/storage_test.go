@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// at returns a local time on day d of March 2024
+func at(d, hour, min int) time.Time {
+	return time.Date(2024, 3, d, hour, min, 0, 0, time.Local)
+}
+
+// newTestStore returns a store in a temporary directory holding samples
+func newTestStore(t *testing.T, samples ...Sample) *Store {
+	t.Helper()
+	s := &Store{
+		dir:                t.TempDir(),
+		retention:          20 * 24 * time.Hour,
+		downsampleAfter:    7 * 24 * time.Hour,
+		downsampleInterval: time.Hour,
+	}
+	for _, sample := range samples {
+		if err := s.Append(sample); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestQuery(t *testing.T) {
+	s := newTestStore(t,
+		Sample{Time: at(2, 10, 0), CPU: 3},
+		Sample{Time: at(1, 23, 0), CPU: 2},
+		Sample{Time: at(1, 9, 0), CPU: 1},
+		Sample{Time: at(3, 8, 0), CPU: 4},
+	)
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("%d history files, want one per day", len(entries))
+	}
+
+	samples, err := s.Query(at(1, 12, 0), at(2, 10, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cpu []float64
+	for _, sample := range samples {
+		cpu = append(cpu, sample.CPU)
+	}
+	if len(cpu) != 2 || cpu[0] != 2 || cpu[1] != 3 {
+		t.Errorf("Query() CPU = %v, want [2 3] in time order", cpu)
+	}
+}
+
+func TestQuerySkipsCorruptLines(t *testing.T) {
+	s := newTestStore(t, Sample{Time: at(1, 9, 0), CPU: 1})
+
+	file, err := os.OpenFile(s.dayPath(at(1, 0, 0)), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString("{not json\n")
+	file.Close()
+	if err := s.Append(Sample{Time: at(1, 10, 0), CPU: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	samples, err := s.Query(at(1, 0, 0), at(2, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 2 {
+		t.Errorf("Query() returned %d samples, want 2", len(samples))
+	}
+}
+
+func TestCompact(t *testing.T) {
+	s := newTestStore(t,
+		// Past retention
+		Sample{Time: at(1, 10, 0), CPU: 50},
+		// Old enough to be downsampled into one sample per hour
+		Sample{Time: at(20, 10, 0), CPU: 10, Memory: 40, MemoryUsed: 100},
+		Sample{Time: at(20, 10, 20), CPU: 30, Memory: 60, MemoryUsed: 300, Disks: []DiskSample{{Mountpoint: "/", Used: 1}}},
+		Sample{Time: at(20, 11, 0), CPU: 5},
+		// Recent
+		Sample{Time: at(30, 10, 0), CPU: 10},
+		Sample{Time: at(30, 10, 20), CPU: 30},
+	)
+
+	if err := s.Compact(at(31, 12, 0)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(s.dayPath(at(1, 0, 0))); !os.IsNotExist(err) {
+		t.Errorf("history past retention was kept: %v", err)
+	}
+
+	old, err := s.Query(at(20, 0, 0), at(20, 23, 59))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(old) != 2 {
+		t.Fatalf("%d samples after downsampling, want 2", len(old))
+	}
+	first := old[0]
+	if !first.Time.Equal(at(20, 10, 0)) || first.CPU != 20 || first.CPUMax != 30 ||
+		first.Memory != 50 || first.MemoryMax != 60 || first.MemoryUsed != 200 || len(first.Disks) != 1 {
+		t.Errorf("downsampled sample = %+v", first)
+	}
+
+	recent, err := s.Query(at(30, 0, 0), at(30, 23, 59))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recent) != 2 {
+		t.Errorf("%d recent samples, want both kept", len(recent))
+	}
+
+	// Compacting again leaves downsampled files as they are
+	if err := s.Compact(at(31, 12, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := s.Query(at(20, 0, 0), at(20, 23, 59)); len(again) != 2 || again[0].CPUMax != 30 {
+		t.Errorf("second compaction changed the history: %+v", again)
+	}
+}
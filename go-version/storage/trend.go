@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"sort"
+	"time"
+)
+
+// Trend summarizes how metrics changed over a period
+type Trend struct {
+	From       time.Time   `json:"from"`
+	To         time.Time   `json:"to"`
+	Samples    int         `json:"samples"`
+	CPUAvg     float64     `json:"cpu_avg"`
+	CPUPeak    float64     `json:"cpu_peak"`
+	MemoryAvg  float64     `json:"memory_avg"`
+	MemoryPeak float64     `json:"memory_peak"`
+	Disks      []DiskTrend `json:"disks,omitempty"`
+}
+
+// DiskTrend is the change in usage of one mountpoint over a period
+type DiskTrend struct {
+	Mountpoint string  `json:"mountpoint"`
+	Used       uint64  `json:"used"`
+	Percent    float64 `json:"percent"`
+	Delta      int64   `json:"delta"`
+}
+
+// Trend summarizes the samples recorded in [from, to]. It returns nil when
+// there is no history for the period.
+func (s *Store) Trend(from, to time.Time) (*Trend, error) {
+	samples, err := s.Query(from, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(samples) == 0 {
+		return nil, nil
+	}
+
+	trend := &Trend{
+		From:    samples[0].Time,
+		To:      samples[len(samples)-1].Time,
+		Samples: len(samples),
+	}
+
+	first := make(map[string]DiskSample)
+	last := make(map[string]DiskSample)
+	for _, sample := range samples {
+		trend.CPUAvg += sample.CPU
+		trend.MemoryAvg += sample.Memory
+		trend.CPUPeak = max(trend.CPUPeak, sample.PeakCPU())
+		trend.MemoryPeak = max(trend.MemoryPeak, sample.PeakMemory())
+
+		for _, disk := range sample.Disks {
+			if _, seen := first[disk.Mountpoint]; !seen {
+				first[disk.Mountpoint] = disk
+			}
+			last[disk.Mountpoint] = disk
+		}
+	}
+
+	n := float64(len(samples))
+	trend.CPUAvg /= n
+	trend.MemoryAvg /= n
+
+	for mountpoint, disk := range last {
+		trend.Disks = append(trend.Disks, DiskTrend{
+			Mountpoint: mountpoint,
+			Used:       disk.Used,
+			Percent:    disk.Percent,
+			Delta:      int64(disk.Used) - int64(first[mountpoint].Used),
+		})
+	}
+
+	sort.Slice(trend.Disks, func(i, j int) bool {
+		return trend.Disks[i].Mountpoint < trend.Disks[j].Mountpoint
+	})
+
+	return trend, nil
+}
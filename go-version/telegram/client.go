@@ -0,0 +1,307 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"system-monitor/config"
+	"time"
+)
+
+const (
+	// maxAttempts is how many times a request is tried before giving up
+	maxAttempts = 4
+	// baseBackoff is the delay before the first retry; it doubles each time
+	baseBackoff = time.Second
+	// maxBackoff caps the delay between retries
+	maxBackoff = 30 * time.Second
+	// requestTimeout bounds a single request unless the context has a deadline
+	requestTimeout = 30 * time.Second
+)
+
+// APIError is an error returned by the Bot API
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+	// RetryAfter is how long Telegram asked to wait before retrying
+	RetryAfter time.Duration
+}
+
+// Error implements error
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+// Retryable reports whether the request may succeed if repeated
+func (e *APIError) Retryable() bool {
+	return e.Code == http.StatusTooManyRequests || e.Code >= http.StatusInternalServerError
+}
+
+// PartialError reports that a split message failed after its first Sent
+// parts were delivered, so a retry should resume with SendMessageFrom
+// instead of sending those parts again
+type PartialError struct {
+	Sent int
+	Err  error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%v (after %d parts were sent)", e.Err, e.Sent)
+}
+
+func (e *PartialError) Unwrap() error {
+	return e.Err
+}
+
+// SentParts returns how many parts were delivered
+func (e *PartialError) SentParts() int {
+	return e.Sent
+}
+
+// Client sends requests to the Telegram Bot API with retries and per-chat
+// rate limiting. It is safe for concurrent use and should be shared, so
+// that all subsystems respect the same limits.
+type Client struct {
+	token   string
+	baseURL string
+	http    *http.Client
+	limiter *rateLimiter
+}
+
+// NewClient creates a client for the bot token and API URL from the config
+func NewClient(cfg *config.Config) *Client {
+	return &Client{
+		token:   cfg.TelegramToken,
+		baseURL: strings.TrimRight(cfg.TelegramAPIURL, "/"),
+		http:    &http.Client{},
+		limiter: newRateLimiter(),
+	}
+}
+
+// response is the envelope of every Bot API response
+type response struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// Call invokes a Bot API method with a JSON payload and decodes the result
+// into result, which may be nil
+func (c *Client) Call(ctx context.Context, method string, payload interface{}, result interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s payload: %w", method, err)
+	}
+
+	return c.do(ctx, method, result, func() (io.Reader, string) {
+		return bytes.NewReader(body), "application/json"
+	})
+}
+
+// SendMessage sends an HTML message, split into several messages on line
+// boundaries when it exceeds Telegram's length limit
+func (c *Client) SendMessage(ctx context.Context, chatID, text string) error {
+	return c.SendMessageMarkup(ctx, chatID, text, nil)
+}
+
+// SendMessageMarkup sends an HTML message with a reply markup such as an
+// inline keyboard; when the text is split, the markup is attached to the
+// last part
+func (c *Client) SendMessageMarkup(ctx context.Context, chatID, text string, markup interface{}) error {
+	return c.sendParts(ctx, chatID, text, markup, 0)
+}
+
+// SendMessageFrom sends an HTML message like SendMessage, skipping the
+// first skip parts, which an earlier attempt reported as sent in a
+// *PartialError
+func (c *Client) SendMessageFrom(ctx context.Context, chatID, text string, skip int) error {
+	return c.sendParts(ctx, chatID, text, nil, skip)
+}
+
+// sendParts splits a message and sends its parts from skip on. A failure
+// after some parts went out is returned as a *PartialError.
+func (c *Client) sendParts(ctx context.Context, chatID, text string, markup interface{}, skip int) error {
+	parts := SplitMessage(text, MaxMessageLength)
+	for i := skip; i < len(parts); i++ {
+		part := parts[i]
+		payload := map[string]interface{}{
+			"chat_id":    chatID,
+			"text":       part,
+			"parse_mode": "HTML",
+		}
+		if markup != nil && i == len(parts)-1 {
+			payload["reply_markup"] = markup
+		}
+
+		err := c.limiter.wait(ctx, chatID)
+		if err == nil {
+			err = c.Call(ctx, "sendMessage", payload, nil)
+		}
+		if err != nil && i > 0 {
+			return &PartialError{Sent: i, Err: err}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AnswerCallbackQuery acknowledges a button press
+func (c *Client) AnswerCallbackQuery(ctx context.Context, queryID string) error {
+	return c.Call(ctx, "answerCallbackQuery", map[string]string{"callback_query_id": queryID}, nil)
+}
+
+// SendPhoto uploads a PNG image with an HTML caption
+func (c *Client) SendPhoto(ctx context.Context, chatID, caption string, photo []byte) error {
+	return c.sendFile(ctx, "sendPhoto", "photo", chatID, caption, "chart.png", photo)
+}
+
+// SendDocument uploads a file with an HTML caption
+func (c *Client) SendDocument(ctx context.Context, chatID, caption, filename string, data []byte) error {
+	return c.sendFile(ctx, "sendDocument", "document", chatID, caption, filename, data)
+}
+
+// sendFile posts a file with an HTML caption to a chat
+func (c *Client) sendFile(ctx context.Context, method, field, chatID, caption, filename string, data []byte) error {
+	if err := c.limiter.wait(ctx, chatID); err != nil {
+		return err
+	}
+
+	fields := map[string]string{
+		"chat_id":    chatID,
+		"caption":    caption,
+		"parse_mode": "HTML",
+	}
+	return c.CallWithFile(ctx, method, fields, field, filename, data)
+}
+
+// CallWithFile invokes a Bot API method with form fields and a file
+// uploaded under the given field, as multipart form data
+func (c *Client) CallWithFile(ctx context.Context, method string, fields map[string]string, field, filename string, data []byte) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		writer.WriteField(name, value)
+	}
+
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", field, err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finish form: %w", err)
+	}
+
+	form := body.Bytes()
+	return c.do(ctx, method, nil, func() (io.Reader, string) {
+		return bytes.NewReader(form), writer.FormDataContentType()
+	})
+}
+
+// do sends a request, retrying network errors, server errors and rate
+// limiting with exponential backoff. body is called for every attempt.
+func (c *Client) do(ctx context.Context, method string, result interface{}, body func() (io.Reader, string)) error {
+	backoff := baseBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := c.attempt(ctx, method, result, body)
+		if err == nil {
+			return nil
+		}
+
+		var apiErr *APIError
+		isAPIErr := errors.As(err, &apiErr)
+		if ctx.Err() != nil || (isAPIErr && !apiErr.Retryable()) || attempt == maxAttempts {
+			return err
+		}
+
+		delay := backoff
+		if isAPIErr && apiErr.RetryAfter > 0 {
+			delay = apiErr.RetryAfter
+		}
+		log.Printf("Telegram %s: %v, повтор через %v", method, err, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// attempt sends a request once and decodes the response envelope
+func (c *Client) attempt(ctx context.Context, method string, result interface{}, body func() (io.Reader, string)) error {
+	reader, contentType := body()
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+	}
+
+	url := fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// The URL in *url.Error contains the bot token, keep only the cause
+		if unwrapped := errors.Unwrap(err); unwrapped != nil {
+			err = unwrapped
+		}
+		return fmt.Errorf("failed to send %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope response
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return &APIError{Method: method, Code: resp.StatusCode, Description: resp.Status}
+		}
+		return fmt.Errorf("failed to decode %s response (status %d): %w", method, resp.StatusCode, err)
+	}
+
+	if !envelope.OK {
+		code := envelope.ErrorCode
+		if code == 0 {
+			code = resp.StatusCode
+		}
+		return &APIError{
+			Method:      method,
+			Code:        code,
+			Description: envelope.Description,
+			RetryAfter:  time.Duration(envelope.Parameters.RetryAfter) * time.Second,
+		}
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(envelope.Result, result); err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"system-monitor/config"
+	"testing"
+	"time"
+)
+
+// messageStub is a Bot API server that records sent texts and rejects the
+// sendMessage requests whose numbers, counted from 1, are in fail
+type messageStub struct {
+	mu    sync.Mutex
+	fail  map[int]bool
+	count int
+	texts []string
+}
+
+func (s *messageStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Text string `json:"text"`
+	}
+	json.NewDecoder(r.Body).Decode(&payload)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.count++
+	if s.fail[s.count] {
+		w.Write([]byte(`{"ok": false, "error_code": 400, "description": "Bad Request: message is too long"}`))
+		return
+	}
+	s.texts = append(s.texts, payload.Text)
+	w.Write([]byte(`{"ok": true, "result": {}}`))
+}
+
+// newStubClient creates a client for a stub server
+func newStubClient(t *testing.T, stub http.Handler) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return NewClient(&config.Config{TelegramToken: "test", TelegramAPIURL: server.URL})
+}
+
+func TestSendMessageResumesAfterPartialFailure(t *testing.T) {
+	line := strings.Repeat("x", 99) + "\n"
+	text := strings.Repeat(line, 100)
+	parts := SplitMessage(text, MaxMessageLength)
+	if len(parts) != 3 {
+		t.Fatalf("test text splits into %d parts, want 3", len(parts))
+	}
+
+	stub := &messageStub{fail: map[int]bool{2: true}}
+	err := newStubClient(t, stub).SendMessage(context.Background(), "42", text)
+
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("error %v, want a partial error", err)
+	}
+	if partial.SentParts() != 1 {
+		t.Errorf("sent parts %d, want 1", partial.SentParts())
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest {
+		t.Errorf("error %v does not wrap the API error", err)
+	}
+	if !reflect.DeepEqual(stub.texts, parts[:1]) {
+		t.Errorf("first attempt sent %d parts, want 1", len(stub.texts))
+	}
+
+	// The retry sends only the parts that did not go out
+	retry := &messageStub{}
+	if err := newStubClient(t, retry).SendMessageFrom(context.Background(), "42", text, partial.SentParts()); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(retry.texts, parts[1:]) {
+		t.Errorf("retry sent %d parts, want the last 2", len(retry.texts))
+	}
+}
+
+func TestSendMessageFirstPartFailure(t *testing.T) {
+	stub := &messageStub{fail: map[int]bool{1: true}}
+	err := newStubClient(t, stub).SendMessage(context.Background(), "42", "hello")
+
+	var partial *PartialError
+	if err == nil || errors.As(err, &partial) {
+		t.Errorf("error %v, want a plain error when nothing was sent", err)
+	}
+}
+
+func TestReserve(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		chats []string
+		want  []time.Duration
+	}{
+		{"private chat", []string{"42", "42", "42"}, []time.Duration{0, time.Second, 2 * time.Second}},
+		{"group chat", []string{"-100", "-100"}, []time.Duration{0, 3 * time.Second}},
+		{"different chats", []string{"42", "43", "44"}, []time.Duration{0, globalInterval, 2 * globalInterval}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newRateLimiter()
+			for i, chat := range tt.chats {
+				if got := l.reserve(chat, start); got != tt.want[i] {
+					t.Errorf("message %d to %s waits %v, want %v", i, chat, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Telegram allows about one message per second in a private chat, twenty
+// per minute in a group and thirty per second overall
+const (
+	privateChatInterval = time.Second
+	groupChatInterval   = 3 * time.Second
+	globalInterval      = time.Second / 30
+)
+
+// rateLimiter spaces out messages per chat and globally
+type rateLimiter struct {
+	next       map[string]time.Time
+	nextGlobal time.Time
+	mu         sync.Mutex
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{next: make(map[string]time.Time)}
+}
+
+// wait blocks until a message may be sent to the chat
+func (l *rateLimiter) wait(ctx context.Context, chatID string) error {
+	delay := l.reserve(chatID, time.Now())
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// reserve books the next free slot for the chat and returns how long to
+// wait for it
+func (l *rateLimiter) reserve(chatID string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Group and channel IDs are negative
+	interval := privateChatInterval
+	if strings.HasPrefix(chatID, "-") {
+		interval = groupChatInterval
+	}
+
+	slot := now
+	if next := l.next[chatID]; next.After(slot) {
+		slot = next
+	}
+	if l.nextGlobal.After(slot) {
+		slot = l.nextGlobal
+	}
+
+	l.next[chatID] = slot.Add(interval)
+	l.nextGlobal = slot.Add(globalInterval)
+
+	// Forget chats that have been quiet, so the map does not grow forever
+	for id, next := range l.next {
+		if now.Sub(next) > time.Minute {
+			delete(l.next, id)
+		}
+	}
+
+	return slot.Sub(now)
+}
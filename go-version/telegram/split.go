@@ -0,0 +1,123 @@
+package telegram
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxMessageLength is the longest text Telegram accepts in one message
+const MaxMessageLength = 4096
+
+// htmlTag matches an opening or closing HTML tag
+var htmlTag = regexp.MustCompile(`<(/?)([a-zA-Z-]+)[^>]*>`)
+
+// openTag is an HTML tag that has not been closed yet
+type openTag struct {
+	name string
+	raw  string
+}
+
+// SplitMessage splits an HTML message into parts of at most limit
+// characters, preferably at line breaks. Tags left open at the end of a
+// part are closed there and reopened in the next one.
+func SplitMessage(text string, limit int) []string {
+	if utf8.RuneCountInString(text) <= limit {
+		return []string{text}
+	}
+
+	var parts []string
+	var chunk strings.Builder
+	var open []openTag
+
+	for _, piece := range pieces(text, limit/2) {
+		after := track(open, piece)
+		size := utf8.RuneCountInString(chunk.String()) + utf8.RuneCountInString(piece) + closingLength(after)
+
+		if size > limit && chunk.Len() > 0 {
+			parts = append(parts, strings.TrimRight(chunk.String(), "\n")+closing(open))
+			chunk.Reset()
+			for _, tag := range open {
+				chunk.WriteString(tag.raw)
+			}
+		}
+
+		chunk.WriteString(piece)
+		open = after
+	}
+
+	if rest := strings.TrimRight(chunk.String(), "\n"); rest != "" {
+		parts = append(parts, rest)
+	}
+
+	return parts
+}
+
+// pieces cuts text into lines, and lines longer than max into pieces that
+// do not end inside a tag or an HTML entity
+func pieces(text string, max int) []string {
+	var result []string
+	for _, line := range strings.SplitAfter(text, "\n") {
+		for utf8.RuneCountInString(line) > max {
+			runes := []rune(line)
+			cut := max
+
+			// Prefer a space, then avoid cutting a tag or entity in half
+			if space := strings.LastIndexAny(string(runes[:cut]), " \t"); space > 0 {
+				cut = utf8.RuneCountInString(string(runes[:cut])[:space+1])
+			}
+			head := string(runes[:cut])
+			if lt := strings.LastIndex(head, "<"); lt > strings.LastIndex(head, ">") && lt > 0 {
+				head = head[:lt]
+			}
+			if amp := strings.LastIndex(head, "&"); amp > strings.LastIndex(head, ";") && amp > 0 {
+				head = head[:amp]
+			}
+
+			result = append(result, head)
+			line = line[len(head):]
+		}
+		if line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// track returns the tags left open after appending piece to text with the
+// given open tags
+func track(open []openTag, piece string) []openTag {
+	result := append([]openTag(nil), open...)
+	for _, match := range htmlTag.FindAllStringSubmatch(piece, -1) {
+		name := strings.ToLower(match[2])
+		if match[1] == "" {
+			result = append(result, openTag{name: name, raw: match[0]})
+			continue
+		}
+		for i := len(result) - 1; i >= 0; i-- {
+			if result[i].name == name {
+				result = append(result[:i], result[i+1:]...)
+				break
+			}
+		}
+	}
+	return result
+}
+
+// closing returns the closing tags for open tags, innermost first
+func closing(open []openTag) string {
+	var b strings.Builder
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i].name + ">")
+	}
+	return b.String()
+}
+
+// closingLength returns the length of the closing tags for open tags
+func closingLength(open []openTag) int {
+	n := 0
+	for _, tag := range open {
+		n += len(tag.name) + 3
+	}
+	return n
+}
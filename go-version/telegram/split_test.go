@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSplitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "short",
+			text:  "<b>ok</b>",
+			limit: 20,
+			want:  []string{"<b>ok</b>"},
+		},
+		{
+			name:  "lines",
+			text:  "first line\nsecond line\nthird line",
+			limit: 24,
+			want:  []string{"first line\nsecond line", "third line"},
+		},
+		{
+			name:  "reopens tags",
+			text:  "<b>first line\nsecond line</b>",
+			limit: 24,
+			want:  []string{"<b>first line</b>", "<b>second line</b>"},
+		},
+		{
+			name:  "nested tags",
+			text:  "<b><i>first line\nsecond</i> line</b>",
+			limit: 30,
+			want:  []string{"<b><i>first line</i></b>", "<b><i>second</i> line</b>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SplitMessage(tt.text, tt.limit)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("SplitMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitMessageLongLine(t *testing.T) {
+	text := "<code>" + strings.Repeat("word &amp; word ", 40) + "</code>"
+	parts := SplitMessage(text, 100)
+	if len(parts) < 2 {
+		t.Fatalf("got %d parts, want several", len(parts))
+	}
+
+	for i, part := range parts {
+		if n := utf8.RuneCountInString(part); n > 100 {
+			t.Errorf("part %d has %d characters", i, n)
+		}
+		if !strings.HasPrefix(part, "<code>") || !strings.HasSuffix(part, "</code>") {
+			t.Errorf("part %d is not wrapped in its tag: %q", i, part)
+		}
+		// An entity cut in half would leave a bare ampersand
+		if strings.Count(part, "&") != strings.Count(part, "&amp;") {
+			t.Errorf("part %d cuts an entity: %q", i, part)
+		}
+	}
+}